@@ -1,8 +1,10 @@
 package cli
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/NimbleArchitect/kubectl-ice/pkg/plugin"
@@ -57,4 +59,20 @@ func InitAndExecute() {
 
 func initConfig() {
 	viper.AutomaticEnv()
+
+	// settings that dont have a flag, such as extra exit codes, are read from $HOME/.config/kubectl-ice/config.yaml
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return
+	}
+	viper.SetConfigName("config")
+	viper.SetConfigType("yaml")
+	viper.AddConfigPath(filepath.Join(home, ".config", "kubectl-ice"))
+
+	if err := viper.ReadInConfig(); err != nil {
+		var notFound viper.ConfigFileNotFoundError
+		if !errors.As(err, &notFound) {
+			fmt.Fprintln(os.Stderr, "warning: unable to read config file:", err)
+		}
+	}
 }
@@ -18,6 +18,12 @@ type Looper interface {
 	HideColumns(info BuilderInformation) []int
 }
 
+// PodLooper is a Looper that builds its rows from the pod itself rather than from each container
+type PodLooper interface {
+	Looper
+	BuildPod(pod v1.Pod, info BuilderInformation) ([][]Cell, error)
+}
+
 type RowBuilder struct {
 	Connection         *Connector
 	Table              *Table
@@ -25,6 +31,7 @@ type RowBuilder struct {
 	PodName            []string // list of pod names to retrieve
 	LoopStatus         bool     // do we need to loop over v1.Pod.Status.ContainerStatus
 	LoopSpec           bool     // should we loop over v1.Pod.Spec.Containers
+	LoopPod            bool     // call BuildPod once for each pod instead of looping over the containers, loop must be a PodLooper
 	LabelNodeName      string
 	labelNodeValue     string
 	LabelPodName       string
@@ -284,6 +291,17 @@ func (b *RowBuilder) walkTreeCreateRow(loop Looper, info *BuilderInformation, pa
 	return totals, nil
 }
 
+// setTableFilter loads the match filter using the headers of a table that is not built by the row builder
+func (b *RowBuilder) setTableFilter(head []string) error {
+	if len(b.FilterList) == 0 {
+		return nil
+	}
+
+	b.head = head
+	b.filter = make([]matchFilter, len(b.head))
+	return b.setFilter(b.FilterList)
+}
+
 // matchShouldExclude checks the match filter and returns true if the row should be excluded from output
 func (b *RowBuilder) matchShouldExclude(tblOut []Cell) bool {
 	var fValue float64
@@ -567,6 +585,11 @@ func (b *RowBuilder) setVisibleColumns(info *BuilderInformation) {
 		b.Table.HideColumn(3)
 	}
 
+	if b.LoopPod {
+		// rows belong to the pod so there is no container name to show
+		b.Table.HideColumn(4)
+	}
+
 }
 
 // PodLoop given a pod we loop over all containers adding to the table as we go
@@ -578,6 +601,23 @@ func (b *RowBuilder) podLoop(loop Looper, info BuilderInformation, pod v1.Pod, i
 	log := logger{location: "RowBuilder:PodLoop"}
 	log.Debug("Start")
 
+	if podLooper, ok := loop.(PodLooper); ok && b.LoopPod {
+		log.Debug("processing LoopPod")
+		info.Name = ""
+		info.TypeName = ""
+		allRows, err := podLooper.BuildPod(pod, info)
+		if err != nil {
+			return [][]Cell{}, err
+		}
+		for _, row := range allRows {
+			rowsOut := b.makeFullRow(&info, indentLevel, row)
+			if !b.matchShouldExclude(rowsOut) {
+				b.Table.AddRow(rowsOut...)
+			}
+		}
+		return allRows, nil
+	}
+
 	if b.ShowInitContainers {
 		log.Debug("loop init Container")
 		info.ContainerType = TypeIDInitContainer
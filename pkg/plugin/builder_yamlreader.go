@@ -75,6 +75,14 @@ func (b *RowBuilder) convertFromYaml(input []byte) (v1.Pod, error) {
 	}
 
 	switch pod.Kind {
+	case "ServiceAccount", "Role", "RoleBinding", "ClusterRole", "ClusterRoleBinding":
+		// keep rbac objects so commands can read them instead of calling the api
+		if b.Connection != nil {
+			if err := b.Connection.addSnapshotRBAC(pod.Kind, input); err != nil {
+				return v1.Pod{}, err
+			}
+		}
+
 	case "Deployment":
 		var deploySpec a1.Deployment
 		err = yaml.Unmarshal(input, &deploySpec)
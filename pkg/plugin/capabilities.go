@@ -1,7 +1,10 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
@@ -11,6 +14,11 @@ import (
 var capabilitiesShort = "Shows details of configured containers POSIX capabilities"
 
 var capabilitiesDescription = ` View POSIX Capabilities that have been applied to the running containers.
+
+Use --effective to show the set of capabilities the container process actually gets, this starts with the
+default set of the container runtime (containerd unless --runtime or --default-caps are used), applies any
+add ALL, then any drop ALL, then the added capabilities and finally the remaining drops. Privileged containers get every
+capability. The RISK column lists any dangerous capabilities found in the effective set.
 `
 
 var capabilitiesExample = `  # List container capabilities from pods
@@ -38,7 +46,10 @@ var capabilitiesExample = `  # List container capabilities from pods
   %[1]s capabilities -l app=web
 
   # List container capabilities info from all pods where the pod label app is either web or mail
-  %[1]s capabilities -l "app in (web,mail)"`
+  %[1]s capabilities -l "app in (web,mail)"
+
+  # List the effective capabilities of each container using the CRI-O default set
+  %[1]s capabilities --effective --runtime crio`
 
 // list details of configured liveness readiness and startup capabilities
 func Capabilities(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
@@ -64,6 +75,23 @@ func Capabilities(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags,
 	builder.Connection = &connect
 	builder.SetFlagsFrom(commonFlagList)
 
+	if cmd.Flag("effective") != nil && cmd.Flag("effective").Value.String() == "true" {
+		loopinfo.ShowEffective = true
+
+		defaults, ok := runtimeDefaultCapabilities[cmd.Flag("runtime").Value.String()]
+		if !ok {
+			return errors.New("error: runtime must be either containerd or crio")
+		}
+
+		if list := cmd.Flag("default-caps").Value.String(); len(list) > 0 {
+			defaults = []string{}
+			for _, name := range strings.Split(list, ",") {
+				defaults = append(defaults, normaliseCapability(name))
+			}
+		}
+		loopinfo.Defaults = defaults
+	}
+
 	table := Table{}
 	builder.Table = &table
 
@@ -82,12 +110,100 @@ func Capabilities(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags,
 
 }
 
+// runtimeDefaultCapabilities the capabilities each container runtime gives a container by default
+var runtimeDefaultCapabilities = map[string][]string{
+	"containerd": {
+		"AUDIT_WRITE", "CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "MKNOD", "NET_BIND_SERVICE",
+		"NET_RAW", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_CHROOT",
+	},
+	"crio": {
+		"CHOWN", "DAC_OVERRIDE", "FOWNER", "FSETID", "KILL", "NET_BIND_SERVICE", "SETGID", "SETPCAP", "SETUID",
+	},
+}
+
+// allCapabilities every linux capability, used when ALL is added or the container is privileged
+var allCapabilities = []string{
+	"AUDIT_CONTROL", "AUDIT_READ", "AUDIT_WRITE", "BLOCK_SUSPEND", "BPF", "CHECKPOINT_RESTORE", "CHOWN",
+	"DAC_OVERRIDE", "DAC_READ_SEARCH", "FOWNER", "FSETID", "IPC_LOCK", "IPC_OWNER", "KILL", "LEASE",
+	"LINUX_IMMUTABLE", "MAC_ADMIN", "MAC_OVERRIDE", "MKNOD", "NET_ADMIN", "NET_BIND_SERVICE", "NET_BROADCAST",
+	"NET_RAW", "PERFMON", "SETFCAP", "SETGID", "SETPCAP", "SETUID", "SYS_ADMIN", "SYS_BOOT", "SYS_CHROOT",
+	"SYS_MODULE", "SYS_NICE", "SYS_PACCT", "SYS_PTRACE", "SYS_RAWIO", "SYS_RESOURCE", "SYS_TIME",
+	"SYS_TTY_CONFIG", "SYSLOG", "WAKE_ALARM",
+}
+
+// riskyCapabilities capabilities that allow a container to break out or attack its neighbours
+var riskyCapabilities = map[string]bool{
+	"BPF": true, "DAC_READ_SEARCH": true, "NET_ADMIN": true, "NET_RAW": true, "SYS_ADMIN": true,
+	"SYS_BOOT": true, "SYS_MODULE": true, "SYS_PTRACE": true, "SYS_RAWIO": true,
+}
+
+// normaliseCapability converts a capability name to upper case without the CAP_ prefix
+func normaliseCapability(name string) string {
+	name = strings.ToUpper(strings.TrimSpace(name))
+	return strings.TrimPrefix(name, "CAP_")
+}
+
+// effectiveCapabilities works out the capabilities the container process gets starting from the
+//
+//	runtime defaults, this follows the container runtime order of add ALL, then drop ALL, then the
+//	remaining adds followed by the remaining drops
+func effectiveCapabilities(securityContext *v1.SecurityContext, defaults []string) []string {
+	set := make(map[string]bool)
+
+	if securityContext != nil && securityContext.Privileged != nil && *securityContext.Privileged {
+		return allCapabilities
+	}
+
+	for _, name := range defaults {
+		set[name] = true
+	}
+
+	if securityContext != nil && securityContext.Capabilities != nil {
+		add := securityContext.Capabilities.Add
+		drop := securityContext.Capabilities.Drop
+
+		for _, name := range add {
+			if normaliseCapability(string(name)) == "ALL" {
+				for _, c := range allCapabilities {
+					set[c] = true
+				}
+			}
+		}
+
+		for _, name := range drop {
+			if normaliseCapability(string(name)) == "ALL" {
+				set = make(map[string]bool)
+			}
+		}
+
+		for _, name := range add {
+			if normaliseCapability(string(name)) != "ALL" {
+				set[normaliseCapability(string(name))] = true
+			}
+		}
+
+		for _, name := range drop {
+			delete(set, normaliseCapability(string(name)))
+		}
+	}
+
+	list := []string{}
+	for name := range set {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
 type capabilities struct {
+	ShowEffective bool
+	Defaults      []string
 }
 
 func (s *capabilities) Headers() []string {
 	return []string{
-		"ADD", "DROP",
+		"ADD", "DROP", "EFFECTIVE", "RISK",
 	}
 }
 
@@ -96,6 +212,9 @@ func (s *capabilities) BuildContainerStatus(container v1.ContainerStatus, info B
 }
 
 func (s *capabilities) HideColumns(info BuilderInformation) []int {
+	if !s.ShowEffective {
+		return []int{2, 3}
+	}
 	return []int{}
 }
 
@@ -103,6 +222,8 @@ func (s *capabilities) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Ce
 	out := []Cell{
 		NewCellText(""),
 		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
 	}
 	return out, nil
 }
@@ -145,9 +266,32 @@ func (s *capabilities) capabilitiesBuildRow(securityContext *v1.SecurityContext,
 		}
 	}
 
+	effective := ""
+	risk := ""
+	if s.ShowEffective {
+		var risky []string
+
+		list := effectiveCapabilities(securityContext, s.Defaults)
+		for _, name := range list {
+			if riskyCapabilities[name] {
+				risky = append(risky, name)
+			}
+		}
+		effective = strings.Join(list, ",")
+		risk = strings.Join(risky, ",")
+
+		if securityContext != nil && securityContext.Privileged != nil && *securityContext.Privileged {
+			// no need to list everything
+			effective = "ALL"
+			risk = "privileged"
+		}
+	}
+
 	cellList = append(cellList,
 		NewCellText(capAdd),
 		NewCellText(capDrop),
+		NewCellText(effective),
+		NewCellText(risk),
 	)
 
 	return cellList
@@ -0,0 +1,66 @@
+package plugin
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// *********************
+// effectiveCapabilities
+// *********************
+type effectiveCapabilitiesTest struct {
+	add      []v1.Capability
+	drop     []v1.Capability
+	expected []string
+}
+
+var effectiveCapabilitiesTests = []effectiveCapabilitiesTest{
+	// no changes keeps the runtime defaults
+	{nil, nil, []string{"CHOWN", "KILL", "SETUID"}},
+	{[]v1.Capability{"NET_ADMIN"}, nil, []string{"CHOWN", "KILL", "NET_ADMIN", "SETUID"}},
+	{nil, []v1.Capability{"KILL"}, []string{"CHOWN", "SETUID"}},
+	// names are matched with or without the CAP_ prefix in any case
+	{[]v1.Capability{"cap_net_admin"}, []v1.Capability{"CAP_KILL"}, []string{"CHOWN", "NET_ADMIN", "SETUID"}},
+	// drop ALL is applied before the adds
+	{[]v1.Capability{"NET_BIND_SERVICE"}, []v1.Capability{"ALL"}, []string{"NET_BIND_SERVICE"}},
+	// add ALL is applied before drop ALL leaving only the individual adds
+	{[]v1.Capability{"ALL"}, []v1.Capability{"ALL"}, []string{}},
+	{[]v1.Capability{"ALL", "CHOWN"}, []v1.Capability{"ALL"}, []string{"CHOWN"}},
+	// individual drops are applied after add ALL
+	{[]v1.Capability{"ALL"}, []v1.Capability{"SYS_ADMIN"}, allCapabilitiesExcept("SYS_ADMIN")},
+	// drops win over adds of the same name
+	{[]v1.Capability{"NET_RAW"}, []v1.Capability{"NET_RAW"}, []string{"CHOWN", "KILL", "SETUID"}},
+}
+
+// allCapabilitiesExcept returns allCapabilities without the named capability
+func allCapabilitiesExcept(name string) []string {
+	list := []string{}
+	for _, c := range allCapabilities {
+		if c != name {
+			list = append(list, c)
+		}
+	}
+	sort.Strings(list)
+	return list
+}
+
+func TestEffectiveCapabilities(t *testing.T) {
+	defaults := []string{"CHOWN", "KILL", "SETUID"}
+
+	for _, test := range effectiveCapabilitiesTests {
+		securityContext := &v1.SecurityContext{Capabilities: &v1.Capabilities{Add: test.add, Drop: test.drop}}
+		if output := effectiveCapabilities(securityContext, defaults); !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("Output %v not equal to expected %v", output, test.expected)
+		}
+	}
+
+	privileged := true
+	securityContext := &v1.SecurityContext{Privileged: &privileged}
+	if output := effectiveCapabilities(securityContext, defaults); !reflect.DeepEqual(output, allCapabilities) {
+		t.Errorf("Output %v not equal to expected %v", output, allCapabilities)
+	}
+
+}
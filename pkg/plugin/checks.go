@@ -0,0 +1,93 @@
+package plugin
+
+import (
+	"encoding/xml"
+	"fmt"
+)
+
+// checkResult is the outcome of a single failed check
+type checkResult struct {
+	name    string // name of the check that failed
+	level   string // the level or severity of the check
+	message string // details of what caused the failure
+}
+
+// checkedContainer holds the failed checks of a single container, containers that passed every
+//
+//	check are kept with no results so they can still be reported
+type checkedContainer struct {
+	namespace     string
+	podName       string
+	containerName string
+	results       []checkResult
+}
+
+// junit* types are the parts of the JUnit XML format understood by Jenkins and GitLab
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	ClassName string         `xml:"classname,attr"`
+	Name      string         `xml:"name,attr"`
+	Failures  []junitFailure `xml:"failure"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// printJUnit prints each checked container as a testcase with a failure for every failed check
+func printJUnit(suiteName string, checked []checkedContainer) error {
+	suite := junitTestSuite{
+		Name:      suiteName,
+		TestCases: []junitTestCase{},
+	}
+
+	for _, container := range checked {
+		testCase := junitTestCase{
+			ClassName: container.namespace + "." + container.podName,
+			Name:      container.containerName,
+		}
+
+		for _, result := range container.results {
+			testCase.Failures = append(testCase.Failures, junitFailure{
+				Message: result.message,
+				Type:    result.level,
+				Text:    fmt.Sprintf("%s (%s): %s", result.name, result.level, result.message),
+			})
+		}
+
+		suite.Tests++
+		if len(testCase.Failures) > 0 {
+			suite.Failures++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	out, err := xml.MarshalIndent(junitTestSuites{
+		Name:     "kubectl-ice",
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Suites:   []junitTestSuite{suite},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(xml.Header + string(out))
+	return nil
+}
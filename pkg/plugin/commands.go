@@ -14,6 +14,11 @@ var commandsDescription = ` Prints command and arguments used to start each cont
 containers can be selected by name.  If no name is specified the container commands of all pods
 in the current namespace are shown.
 
+Use --image-config-dir to point at a directory holding OCI image layouts or docker save output (either
+extracted or as .tar files), the image config is then used to fill in the values a container gets from its
+image. IMAGE_COMMAND and IMAGE_ARGUMENTS are only shown when the image ENTRYPOINT and CMD are used, along with
+the working directory, USER and exposed ports from the image.
+
 The T column in the table output denotes S for Standard and I for init containers`
 
 var commandsExample = `  # List containers command info from pods
@@ -41,11 +46,16 @@ var commandsExample = `  # List containers command info from pods
   %[1]s command -l app=web
 
   # List container command info from all pods where the pod label app is either web or mail
-  %[1]s command -l "app in (web,mail)"`
+  %[1]s command -l "app in (web,mail)"
+
+  # List the command each container runs including the ENTRYPOINT and CMD from images saved locally
+  %[1]s command --image-config-dir ./images`
 
 type commandLine struct {
-	cmd  []string
-	args []string
+	cmd        []string
+	args       []string
+	image      string
+	workingDir string
 }
 
 func Commands(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
@@ -72,6 +82,13 @@ func Commands(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 	builder.Connection = &connect
 	builder.SetFlagsFrom(commonFlagList)
 
+	if dir := cmd.Flag("image-config-dir").Value.String(); len(dir) > 0 {
+		loopinfo.ImageConfigs, err = loadImageConfigDir(dir)
+		if err != nil {
+			return err
+		}
+	}
+
 	table := Table{}
 	builder.Table = &table
 	builder.ShowTreeView = commonFlagList.showTreeView
@@ -90,11 +107,13 @@ func Commands(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 }
 
 type commands struct {
+	ImageConfigs *imageConfigStore
 }
 
 func (s *commands) Headers() []string {
 	return []string{
 		"COMMAND", "ARGUMENTS",
+		"IMAGE_COMMAND", "IMAGE_ARGUMENTS", "WORKDIR", "IMAGE_WORKDIR", "IMAGE_USER", "IMAGE_PORTS",
 	}
 }
 
@@ -103,20 +122,22 @@ func (s *commands) BuildContainerStatus(container v1.ContainerStatus, info Build
 }
 
 func (s *commands) HideColumns(info BuilderInformation) []int {
+	if s.ImageConfigs == nil {
+		return []int{2, 3, 4, 5, 6, 7}
+	}
 	return []int{}
 }
 
 func (s *commands) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	out := []Cell{
-		NewCellText(""),
-		NewCellText("")}
-	return out, nil
+	return make([]Cell, len(s.Headers())), nil
 }
 
 func (s *commands) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
 	cmdLine := commandLine{
-		cmd:  container.Command,
-		args: container.Args,
+		cmd:        container.Command,
+		args:       container.Args,
+		image:      container.Image,
+		workingDir: container.WorkingDir,
 	}
 	out := make([][]Cell, 1)
 	out[0] = s.commandsBuildRow(cmdLine, info)
@@ -125,8 +146,10 @@ func (s *commands) BuildContainerSpec(container v1.Container, info BuilderInform
 
 func (s *commands) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
 	cmdLine := commandLine{
-		cmd:  container.Command,
-		args: container.Args,
+		cmd:        container.Command,
+		args:       container.Args,
+		image:      container.Image,
+		workingDir: container.WorkingDir,
 	}
 	out := make([][]Cell, 1)
 	out[0] = s.commandsBuildRow(cmdLine, info)
@@ -135,10 +158,34 @@ func (s *commands) BuildEphemeralContainerSpec(container v1.EphemeralContainer,
 
 func (s *commands) commandsBuildRow(cmdLine commandLine, info BuilderInformation) []Cell {
 	var cellList []Cell
+	var imageCommand, imageArgs, imageWorkDir, imageUser, imagePorts Cell
+
+	if config, ok := s.ImageConfigs.Get(cmdLine.image); ok {
+		// the image CMD is ignored when the container sets its own command
+		if len(cmdLine.cmd) == 0 {
+			imageCommand = NewCellText(strings.Join(config.Entrypoint, " "))
+			if len(cmdLine.args) == 0 {
+				imageArgs = NewCellText(strings.Join(config.Cmd, " "))
+			}
+		}
+
+		if len(cmdLine.workingDir) == 0 {
+			imageWorkDir = NewCellText(config.WorkingDir)
+		}
+
+		imageUser = NewCellText(imageUserName(config))
+		imagePorts = NewCellText(strings.Join(config.Ports(), ","))
+	}
 
 	cellList = append(cellList,
 		NewCellText(strings.Join(cmdLine.cmd, " ")),
 		NewCellText(strings.Join(cmdLine.args, " ")),
+		imageCommand,
+		imageArgs,
+		NewCellText(cmdLine.workingDir),
+		imageWorkDir,
+		imageUser,
+		imagePorts,
 	)
 
 	return cellList
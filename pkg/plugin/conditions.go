@@ -0,0 +1,173 @@
+package plugin
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var conditionsShort = "List the conditions and readiness gates of each pod"
+
+var conditionsDescription = ` Prints every condition of each pod, such as PodScheduled, Initialized, ContainersReady, Ready and
+DisruptionTarget along with any custom readiness gate conditions. Each condition shows its status, reason, when
+it last changed and how long ago that was. This can be used to explain pods that are Running but not Ready.
+
+Readiness gates from the pods spec.readinessGates are marked in the GATE column, a readiness gate without a
+matching condition is shown with a status of Missing as the pod cant become Ready until it is set.`
+
+var conditionsExample = `  # List the conditions of pods in the current namespace
+  %[1]s conditions
+
+  # List the conditions of a single pod
+  %[1]s conditions my-pod-4jh36
+
+  # List conditions that are not True in all namespaces
+  %[1]s conditions -A -m "STATUS!=True"
+
+  # List readiness gates and their status
+  %[1]s conditions -m "GATE=true"`
+
+func Conditions(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Conditions"}
+	log.Debug("Start")
+
+	loopinfo := conditions{}
+	builder := RowBuilder{}
+	builder.LoopPod = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type conditions struct {
+}
+
+func (s *conditions) Headers() []string {
+	return []string{
+		"CONDITION", "GATE", "STATUS", "REASON", "LAST-TRANSITION", "AGE", "MESSAGE",
+	}
+}
+
+func (s *conditions) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *conditions) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *conditions) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *conditions) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *conditions) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, 7)
+
+	// "CONDITION", "GATE", "STATUS", "REASON", "LAST-TRANSITION", "AGE", "MESSAGE"
+	if info.TypeName == TypeNamePod {
+		// the pod row shows its Ready condition
+		for _, condition := range info.Data.pod.Status.Conditions {
+			if condition.Type == v1.PodReady {
+				copy(rowOut, s.conditionsBuildRow(condition, false))
+			}
+		}
+		return rowOut, nil
+	}
+
+	// owners are only ready when all the pods below them are
+	ready := "True"
+	for _, r := range rows {
+		if r[2].text != "True" {
+			ready = "False"
+		}
+	}
+	rowOut[0] = NewCellText(string(v1.PodReady))
+	rowOut[2] = NewCellText(ready)
+
+	return rowOut, nil
+}
+
+func (s *conditions) BuildPod(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	out := [][]Cell{}
+
+	gates := make(map[v1.PodConditionType]bool)
+	for _, gate := range pod.Spec.ReadinessGates {
+		gates[gate.ConditionType] = true
+	}
+
+	found := make(map[v1.PodConditionType]bool)
+	for _, condition := range pod.Status.Conditions {
+		found[condition.Type] = true
+		out = append(out, s.conditionsBuildRow(condition, gates[condition.Type]))
+	}
+
+	for _, gate := range pod.Spec.ReadinessGates {
+		if found[gate.ConditionType] {
+			continue
+		}
+		out = append(out, []Cell{
+			NewCellText(string(gate.ConditionType)),
+			NewCellText("true"),
+			NewCellText("Missing"),
+			Cell{},
+			Cell{},
+			Cell{},
+			NewCellText("readiness gate has no matching condition"),
+		})
+	}
+
+	return out, nil
+}
+
+func (s *conditions) conditionsBuildRow(condition v1.PodCondition, gate bool) []Cell {
+	var lastTransition, age Cell
+
+	if !condition.LastTransitionTime.IsZero() {
+		lastTransition = NewCellText(condition.LastTransitionTime.Format(timestampFormat))
+		age = ageCell(condition.LastTransitionTime.Time)
+	}
+
+	return []Cell{
+		NewCellText(string(condition.Type)),
+		NewCellText(fmt.Sprintf("%t", gate)),
+		NewCellText(string(condition.Status)),
+		NewCellText(condition.Reason),
+		lastTransition,
+		age,
+		NewCellText(condition.Message),
+	}
+}
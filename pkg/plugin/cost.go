@@ -0,0 +1,370 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	"sigs.k8s.io/yaml"
+)
+
+var costShort = "Estimate the hourly and monthly cost of each container from a local price sheet"
+
+var costDescription = ` Prints the estimated hourly and monthly cost of each container using the prices listed in a local
+yaml price sheet, by default the cost is worked out from the containers requests, use --by usage to
+base the cost on the current metrics instead. A month is counted as 730 hours.
+
+Prices are per vCPU hour and per GiB hour, they can be keyed by a node label such as the instance type
+so pods are priced by the node they are running on, nodes without a matching price use the default.
+
+  nodeLabel: node.kubernetes.io/instance-type
+  default:
+    cpu: 0.0316
+    memory: 0.0042
+  prices:
+    m5.large:
+      cpu: 0.048
+      memory: 0.006
+
+Init containers have finished by the time the pod is running so they are shown with a cost of zero
+when using -i, sidecar init containers keep running and are costed the same as app containers.
+
+In tree view the costs are added up for each owner, namespace and node, pods hidden by --match are left out
+of the namespace totals.`
+
+var costExample = `  # List the cost of containers in the current namespace
+  %[1]s cost --prices prices.yaml
+
+  # List the cost of containers based on their current usage
+  %[1]s cost --prices prices.yaml --by usage
+
+  # Add up the cost of each deployment and namespace
+  %[1]s cost --prices prices.yaml -A --tree
+
+  # Add up the cost of each node
+  %[1]s cost --prices prices.yaml -A --node-tree`
+
+const hoursPerMonth = 730.0
+
+// price is the cost of one vCPU and one GiB of memory for an hour
+type price struct {
+	CPU    float64 `json:"cpu"`
+	Memory float64 `json:"memory"`
+}
+
+// priceSheet is the layout of the yaml file passed with --prices
+type priceSheet struct {
+	NodeLabel string           `json:"nodeLabel"`
+	Default   price            `json:"default"`
+	Prices    map[string]price `json:"prices"`
+}
+
+func Cost(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Cost"}
+	log.Debug("Start")
+
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+
+	loopinfo := cost{}
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	loopinfo.Builder = &builder
+	loopinfo.Connection = &connect
+	loopinfo.BytesAs = "Gi"
+
+	sheet, err := loadPriceSheet(cmd.Flag("prices").Value.String())
+	if err != nil {
+		return err
+	}
+	loopinfo.Sheet = sheet
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	loopinfo.LiveData = len(commonFlagList.inputFilename) == 0 && !stdinChanged
+
+	switch cmd.Flag("by").Value.String() {
+	case "requests", "request", "":
+	case "usage":
+		if !loopinfo.LiveData {
+			return errors.New("error: cost by usage needs live metrics and can not be used when reading from a file")
+		}
+		if err := connect.LoadMetricConfig(kubeFlags); err != nil {
+			return err
+		}
+		podStateList, err := connect.GetMetricPods(args)
+		if err != nil {
+			return err
+		}
+		loopinfo.ByUsage = true
+		loopinfo.MetricsResource = (&resource{}).podMetrics2Hashtable(podStateList)
+	default:
+		return errors.New("error: --by must be either requests or usage")
+	}
+
+	if cmd.Flag("size") != nil {
+		if len(cmd.Flag("size").Value.String()) > 0 {
+			loopinfo.BytesAs = cmd.Flag("size").Value.String()
+		}
+	}
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	// namespace totals are added before sorting so they are ordered along with the other rows
+	if builder.ShowTreeView {
+		loopinfo.addNamespaceRows(&builder)
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+// loadPriceSheet reads the named price sheet yaml file
+func loadPriceSheet(filename string) (priceSheet, error) {
+	sheet := priceSheet{}
+
+	if len(filename) == 0 {
+		return sheet, errors.New("error: a price sheet is required, use --prices to set the file name")
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return sheet, fmt.Errorf("unable to read price sheet: %w", err)
+	}
+
+	if err := yaml.Unmarshal(data, &sheet); err != nil {
+		return sheet, fmt.Errorf("unable to parse price sheet: %w", err)
+	}
+
+	return sheet, nil
+}
+
+type cost struct {
+	Builder         *RowBuilder // used to leave pods hidden by --match out of the namespace totals, can be nil
+	Connection      *Connector
+	MetricsResource map[string]map[string]v1.ResourceList
+	Sheet           priceSheet
+	BytesAs         string
+	ByUsage         bool
+	LiveData        bool
+	namespaceTotals map[string][]Cell
+}
+
+func (s *cost) Headers() []string {
+	return []string{
+		"CPU", "MEMORY", "PRICE", "HOURLY", "MONTHLY",
+	}
+}
+
+func (s *cost) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *cost) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *cost) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, 5)
+
+	// "CPU", "MEMORY", "PRICE", "HOURLY", "MONTHLY"
+	for _, r := range rows {
+		rowOut[0].number += r[0].number
+		rowOut[1].number += r[1].number
+		rowOut[3].float += r[3].float
+		rowOut[4].float += r[4].float
+	}
+
+	if info.TypeName == TypeNamePod {
+		pod := info.Data.pod
+		if !s.ByUsage {
+			// pods are charged for what they reserve on the node not the sum of their containers
+			cpu, _, _ := podEffectiveResources(pod, v1.ResourceCPU)
+			memory, _, _ := podEffectiveResources(pod, v1.ResourceMemory)
+			rowOut[0].number = cpu.MilliValue()
+			rowOut[1].number = memory.Value()
+		}
+
+		key, p := s.priceFor(pod.Spec.NodeName)
+		rowOut[2] = NewCellText(key)
+		rowOut[3].float = s.hourlyCost(rowOut[0].number, rowOut[1].number, p)
+		rowOut[4].float = rowOut[3].float * hoursPerMonth
+	}
+
+	rowOut[0] = NewCellInt(fmt.Sprintf("%dm", rowOut[0].number), rowOut[0].number)
+	rowOut[1] = NewCellInt(memoryHumanReadable(rowOut[1].number, s.BytesAs), rowOut[1].number)
+	rowOut[3] = NewCellFloat(fmt.Sprintf("%.4f", rowOut[3].float), rowOut[3].float)
+	rowOut[4] = NewCellFloat(fmt.Sprintf("%.2f", rowOut[4].float), rowOut[4].float)
+
+	if info.TypeName == TypeNamePod {
+		// pods hidden by --match are not added to the namespace total
+		if s.Builder == nil || !s.Builder.matchShouldExclude(s.Builder.makeFullRow(&info, 0, rowOut)) {
+			s.addNamespaceTotal(info.Data.pod.Namespace, rowOut)
+		}
+	}
+
+	return rowOut, nil
+}
+
+func (s *cost) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	out := make([][]Cell, 1)
+	out[0] = s.costBuildRow(container.Resources, info)
+	return out, nil
+}
+
+func (s *cost) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	out := make([][]Cell, 1)
+	out[0] = s.costBuildRow(container.Resources, info)
+	return out, nil
+}
+
+func (s *cost) costBuildRow(res v1.ResourceRequirements, info BuilderInformation) []Cell {
+	var cpu, memory int64
+
+	log := logger{location: "cost:costBuildRow"}
+	log.Debug("Start")
+
+	list := res.Requests
+	if s.ByUsage {
+		list = s.MetricsResource[info.PodName][info.Name]
+	}
+
+	cpu = list.Cpu().MilliValue()
+	memory = list.Memory().Value()
+
+	key, p := s.priceFor(info.NodeName)
+	hourly := s.hourlyCost(cpu, memory, p)
+
+	if info.ContainerType == TypeIDInitContainer && !isRestartableInitContainer(info.Data.pod, info.Name) {
+		// init containers have finished before the pod starts so they add nothing to its running cost
+		hourly = 0
+	}
+
+	return []Cell{
+		NewCellInt(fmt.Sprintf("%dm", cpu), cpu),
+		NewCellInt(memoryHumanReadable(memory, s.BytesAs), memory),
+		NewCellText(key),
+		NewCellFloat(fmt.Sprintf("%.4f", hourly), hourly),
+		NewCellFloat(fmt.Sprintf("%.2f", hourly*hoursPerMonth), hourly*hoursPerMonth),
+	}
+}
+
+// priceFor returns the price and its name for the named node, the default price is used
+//
+//	when the node has no matching label or the node details are not available
+func (s *cost) priceFor(nodeName string) (string, price) {
+	log := logger{location: "cost:priceFor"}
+
+	if !s.LiveData || len(s.Sheet.NodeLabel) == 0 || len(nodeName) == 0 {
+		return "default", s.Sheet.Default
+	}
+
+	node, err := s.Connection.GetNode(nodeName)
+	if err != nil {
+		log.Debug(err)
+		return "default", s.Sheet.Default
+	}
+
+	value := node.Labels[s.Sheet.NodeLabel]
+	if p, ok := s.Sheet.Prices[value]; ok {
+		return value, p
+	}
+
+	return "default", s.Sheet.Default
+}
+
+// hourlyCost works out the cost of running millicores of cpu and bytes of memory for an hour
+func (s *cost) hourlyCost(millicores int64, bytes int64, p price) float64 {
+	gib := float64(bytes) / (1024 * 1024 * 1024)
+	return validateFloat64(float64(millicores)/1000*p.CPU + gib*p.Memory)
+}
+
+// addNamespaceTotal adds the cost of a pod to the running total of its namespace
+func (s *cost) addNamespaceTotal(namespace string, row []Cell) {
+	if s.namespaceTotals == nil {
+		s.namespaceTotals = make(map[string][]Cell)
+	}
+
+	total, ok := s.namespaceTotals[namespace]
+	if !ok {
+		total = make([]Cell, 5)
+	}
+
+	total[0].number += row[0].number
+	total[1].number += row[1].number
+	total[3].float += row[3].float
+	total[4].float += row[4].float
+	s.namespaceTotals[namespace] = total
+}
+
+// addNamespaceRows adds a total row for each namespace to the end of the table
+func (s *cost) addNamespaceRows(builder *RowBuilder) {
+	var names []string
+
+	for name := range s.namespaceTotals {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// namespace rows sit at the same level as the tree roots
+	indent := 1
+	if builder.ShowNodeTree {
+		indent = 0
+	}
+
+	for _, name := range names {
+		total := s.namespaceTotals[name]
+		info := BuilderInformation{
+			TreeView:      true,
+			Namespace:     name,
+			Name:          name,
+			ContainerType: TypeIDNamespace,
+			TypeName:      TypeNameNamespace,
+		}
+
+		row := []Cell{
+			NewCellInt(fmt.Sprintf("%dm", total[0].number), total[0].number),
+			NewCellInt(memoryHumanReadable(total[1].number, s.BytesAs), total[1].number),
+			NewCellText(""),
+			NewCellFloat(fmt.Sprintf("%.4f", total[3].float), total[3].float),
+			NewCellFloat(fmt.Sprintf("%.2f", total[4].float), total[4].float),
+		}
+
+		tblOut := builder.makeFullRow(&info, indent, row)
+		if builder.matchShouldExclude(tblOut) {
+			continue
+		}
+		builder.Table.AddRow(tblOut...)
+	}
+}
@@ -0,0 +1,57 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func costTestInfo(name string, cpu string) BuilderInformation {
+	info := BuilderInformation{TreeView: true, Name: name, TypeName: TypeNamePod}
+	info.Data.pod.Name = name
+	info.Data.pod.Namespace = "web"
+	info.Data.pod.Spec.Containers = []v1.Container{{Resources: v1.ResourceRequirements{
+		Requests: v1.ResourceList{v1.ResourceCPU: apires.MustParse(cpu)},
+	}}}
+	return info
+}
+
+// *****************
+// addNamespaceTotal
+// *****************
+func TestCostNamespaceTotal(t *testing.T) {
+	s := cost{Sheet: priceSheet{Default: price{CPU: 0.05}}}
+
+	builder := RowBuilder{Table: &Table{}, ShowTreeView: true}
+	builder.FilterList = map[string]matchValue{"MONTHLY": {operator: ">", value: "10"}}
+	if err := builder.LoadHeaders(&s, &BuilderInformation{TreeView: true}); err != nil {
+		t.Fatal(err)
+	}
+	s.Builder = &builder
+
+	// 1 cpu costs 36.50 a month and 100m costs 3.65 so only the first pod matches
+	for _, info := range []BuilderInformation{costTestInfo("large", "1"), costTestInfo("small", "100m")} {
+		if _, err := s.BuildBranch(info, [][]Cell{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	total := s.namespaceTotals["web"]
+	if total[0].number != 1000 {
+		t.Errorf("Output %dm not equal to expected 1000m", total[0].number)
+	}
+
+	// without a filter every pod is counted
+	s = cost{Sheet: priceSheet{Default: price{CPU: 0.05}}}
+	for _, info := range []BuilderInformation{costTestInfo("large", "1"), costTestInfo("small", "100m")} {
+		if _, err := s.BuildBranch(info, [][]Cell{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if total := s.namespaceTotals["web"]; total[0].number != 1100 {
+		t.Errorf("Output %dm not equal to expected 1100m", total[0].number)
+	}
+
+}
@@ -0,0 +1,474 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var diagnoseShort = "List the likely causes of containers not being ready"
+
+var diagnoseDescription = ` Looks at each container that is not ready and lists the likely causes, the most likely cause is
+ranked first. Each cause is shown with the evidence it was found from, this comes from the current and last
+termination state, restart count, back-off reason, exit code meaning, probe failures, missing ConfigMap and
+Secret references and image pull errors.
+
+The following causes can be listed:
+  unschedulable      the pod has not been scheduled to a node
+  image-pull         the image could not be pulled
+  missing-config     a ConfigMap or Secret the container uses does not exist
+  create-failed      the container runtime could not create or start the container
+  mount-failed       a volume could not be attached or mounted
+  out-of-memory      the container was OOMKilled
+  startup-probe      the startup probe is failing
+  liveness-probe     the liveness probe is failing, causing the container to be restarted
+  application-error  the container exited with a non zero exit code
+  readiness-probe    the readiness probe is failing
+  crash-loop         the container is restarting but the exit code is unknown
+  starting           the container is still being created
+  not-ready          no cause could be found
+
+Events and the ConfigMap and Secret checks are only used when connected to a live cluster.`
+
+var diagnoseExample = `  # List the likely causes of containers not being ready in the current namespace
+  %[1]s diagnose
+
+  # Find out why the containers in a single pod are not ready
+  %[1]s why my-pod-4jh36
+
+  # Only show the most likely cause for each container
+  %[1]s diagnose -m "RANK=1"
+
+  # List the likely causes of containers not being ready in all namespaces
+  %[1]s diagnose -A`
+
+func Diagnose(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Diagnose"}
+	log.Debug("Start")
+
+	loopinfo := diagnose{}
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	loopinfo.Live = len(commonFlagList.inputFilename) == 0 && !stdinChanged
+	loopinfo.Connection = &connect
+
+	loopinfo.ExitCodes, err = loadExitCodeMeanings()
+	if err != nil {
+		return err
+	}
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+// diagnosis is a likely cause of a container not being ready, causes with a higher score are ranked first
+type diagnosis struct {
+	score    int
+	cause    string
+	evidence string
+}
+
+type diagnose struct {
+	Connection *Connector
+	Live       bool // events and object lookups are only made against a live cluster
+	ExitCodes  map[int32]string
+}
+
+func (s *diagnose) Headers() []string {
+	return []string{
+		"RESTARTS", "STATE", "REASON", "RANK", "CAUSE", "EVIDENCE",
+	}
+}
+
+func (s *diagnose) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *diagnose) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *diagnose) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, 6)
+
+	if info.TypeName != TypeNamePod {
+		return rowOut, nil
+	}
+
+	// rows are repeated for each cause so the restarts are counted from the pod instead
+	pod := info.Data.pod
+	var restarts int64
+	for _, list := range [][]v1.ContainerStatus{pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses} {
+		for _, status := range list {
+			restarts += int64(status.RestartCount)
+		}
+	}
+
+	rowOut[0] = NewCellInt(fmt.Sprintf("%d", restarts), restarts)
+	rowOut[1] = NewCellText(string(pod.Status.Phase))
+	rowOut[2] = NewCellText(pod.Status.Reason)
+
+	return rowOut, nil
+}
+
+func (s *diagnose) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	out := [][]Cell{}
+
+	pod := info.Data.pod
+	if pod.Status.Phase == v1.PodSucceeded {
+		// all containers have finished so they are never going to be ready
+		return out, nil
+	}
+
+	containerStatus := containerStatusByName(pod, container.Name)
+	if containerStatus != nil && containerStatus.Ready {
+		return out, nil
+	}
+
+	list, err := s.diagnoseContainer(info, container, containerStatus)
+	if err != nil {
+		return out, err
+	}
+
+	// the restart count, state and reason are the same as the status command shows
+	stateCells := make([]Cell, 3)
+	if containerStatus != nil {
+		st := status{ExitCodes: s.ExitCodes}
+		statusRows, err := st.BuildContainerStatus(*containerStatus, info)
+		if err != nil {
+			return out, err
+		}
+		copy(stateCells, statusRows[0][2:5])
+	}
+
+	for i, d := range list {
+		rank := int64(i + 1)
+		out = append(out, []Cell{
+			stateCells[0],
+			stateCells[1],
+			stateCells[2],
+			NewCellInt(fmt.Sprintf("%d", rank), rank),
+			NewCellText(d.cause),
+			NewCellText(d.evidence),
+		})
+	}
+
+	return out, nil
+}
+
+func (s *diagnose) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	// ephemeral containers are never marked as ready so there is nothing to diagnose
+	return [][]Cell{}, nil
+}
+
+// diagnoseContainer works out the likely causes of a container not being ready, the list is returned with the
+//
+//	most likely cause first
+func (s *diagnose) diagnoseContainer(info BuilderInformation, container v1.Container, containerStatus *v1.ContainerStatus) ([]diagnosis, error) {
+	var list []diagnosis
+	var waiting *v1.ContainerStateWaiting
+
+	pod := info.Data.pod
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodScheduled && condition.Status == v1.ConditionFalse {
+			list = append(list, diagnosis{100, "unschedulable", joinEvidence(condition.Reason, condition.Message)})
+		}
+	}
+
+	if containerStatus != nil {
+		waiting = containerStatus.State.Waiting
+	}
+
+	if waiting != nil {
+		switch waiting.Reason {
+		case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "ErrImageNeverPull":
+			list = append(list, diagnosis{95, "image-pull", joinEvidence(waiting.Reason, waiting.Message)})
+		case "CreateContainerError", "RunContainerError", "PreCreateHookError", "PreStartHookError":
+			list = append(list, diagnosis{90, "create-failed", joinEvidence(waiting.Reason, waiting.Message)})
+		}
+	}
+
+	missing, err := s.missingReferences(pod, container)
+	if err != nil {
+		return list, err
+	}
+	if waiting != nil && waiting.Reason == "CreateContainerConfigError" {
+		missing = append([]string{joinEvidence(waiting.Reason, waiting.Message)}, missing...)
+	}
+	if len(missing) > 0 {
+		list = append(list, diagnosis{95, "missing-config", strings.Join(missing, ", ")})
+	}
+
+	if containerStatus != nil {
+		list = append(list, s.terminationCauses(container, *containerStatus)...)
+	}
+
+	if s.Live {
+		eventCauses, err := s.eventCauses(info, container)
+		if err != nil {
+			return list, err
+		}
+		list = append(list, eventCauses...)
+	}
+
+	if len(list) == 0 {
+		switch {
+		case containerStatus == nil:
+			list = append(list, diagnosis{10, "starting", "the container has no status yet"})
+		case waiting != nil && (waiting.Reason == "ContainerCreating" || waiting.Reason == "PodInitializing"):
+			list = append(list, diagnosis{10, "starting", waiting.Reason})
+		default:
+			list = append(list, diagnosis{0, "not-ready", "no cause found, the container is not ready"})
+		}
+	}
+
+	sort.SliceStable(list, func(i, j int) bool {
+		return list[i].score > list[j].score
+	})
+
+	return list, nil
+}
+
+// terminationCauses checks the current and last termination state for OOM kills and non zero exit codes
+func (s *diagnose) terminationCauses(container v1.Container, containerStatus v1.ContainerStatus) []diagnosis {
+	var list []diagnosis
+
+	backOff := ""
+	if waiting := containerStatus.State.Waiting; waiting != nil && waiting.Reason == "CrashLoopBackOff" {
+		backOff = joinEvidence(waiting.Reason, waiting.Message)
+	}
+
+	term := containerStatus.State.Terminated
+	when := "exited"
+	if term == nil {
+		term = containerStatus.LastTerminationState.Terminated
+		when = "last exited"
+	}
+
+	if term == nil {
+		if len(backOff) > 0 {
+			list = append(list, diagnosis{50, "crash-loop", fmt.Sprintf("restarted %d times, %s", containerStatus.RestartCount, backOff)})
+		}
+		return list
+	}
+
+	evidence := []string{}
+	score := 70
+	cause := "application-error"
+
+	switch {
+	case term.Reason == "OOMKilled":
+		score = 85
+		cause = "out-of-memory"
+		limit := "no memory limit set"
+		if memory, ok := container.Resources.Limits[v1.ResourceMemory]; ok {
+			limit = "memory limit " + memory.String()
+		}
+		evidence = append(evidence, fmt.Sprintf("%s with code %d OOMKilled", when, term.ExitCode), limit)
+
+	case term.ExitCode != 0:
+		meaning := exitCodeMeaning(s.ExitCodes, term.ExitCode, term.Signal, term.Reason)
+		evidence = append(evidence, fmt.Sprintf("%s with code %d", when, term.ExitCode))
+		if len(meaning) > 0 {
+			evidence = append(evidence, meaning)
+		}
+		if len(term.Message) > 0 {
+			evidence = append(evidence, strings.TrimSpace(term.Message))
+		}
+
+	default:
+		if len(backOff) == 0 {
+			return list
+		}
+		score = 50
+		cause = "crash-loop"
+		evidence = append(evidence, when+" with code 0")
+	}
+
+	evidence = append(evidence, fmt.Sprintf("restarted %d times", containerStatus.RestartCount))
+	if len(backOff) > 0 {
+		evidence = append(evidence, backOff)
+	}
+
+	return append(list, diagnosis{score, cause, strings.Join(evidence, ", ")})
+}
+
+// eventCauses checks the pods events for probe and volume mount failures
+func (s *diagnose) eventCauses(info BuilderInformation, container v1.Container) ([]diagnosis, error) {
+	var list []diagnosis
+
+	probeList := probes{Connection: s.Connection}
+	scores := map[string]int{"startup": 80, "liveness": 80, "readiness": 60}
+	for _, action := range probeList.buildProbeList(container) {
+		cells, err := probeList.probeEventCells(info, container.Name, action.probeName)
+		if err != nil {
+			return list, err
+		}
+		if cells[1].number == 0 {
+			continue
+		}
+
+		evidence := fmt.Sprintf("%d failures, last %s ago", cells[1].number, cells[0].text)
+		if len(cells[2].text) > 0 {
+			evidence += ": " + cells[2].text
+		}
+		if problems := probeList.validateProbe(info, container, action); problems != "ok" {
+			evidence += ", " + problems
+		}
+		list = append(list, diagnosis{scores[action.probeName], action.probeName + "-probe", evidence})
+	}
+
+	events, err := s.Connection.GetPodEvents(info.Data.pod)
+	if err != nil {
+		return list, err
+	}
+
+	var count int64
+	var latest *v1.Event
+	for i, event := range events {
+		if event.Reason != "FailedMount" && event.Reason != "FailedAttachVolume" {
+			continue
+		}
+		count += eventCount(event)
+		if latest == nil || eventLastSeen(event).After(eventLastSeen(*latest)) {
+			latest = &events[i]
+		}
+	}
+	if latest != nil {
+		evidence := fmt.Sprintf("%d failures, last: %s", count, latest.Message)
+		list = append(list, diagnosis{85, "mount-failed", evidence})
+	}
+
+	return list, nil
+}
+
+// missingReferences lists the ConfigMaps and Secrets used by the container that dont exist, optional
+//
+//	references are skipped and nothing is checked when reading from a file
+func (s *diagnose) missingReferences(pod v1.Pod, container v1.Container) ([]string, error) {
+	var missing []string
+
+	if !s.Live {
+		return missing, nil
+	}
+
+	type reference struct {
+		kind string
+		name string
+	}
+	refs := []reference{}
+
+	isOptional := func(optional *bool) bool {
+		return optional != nil && *optional
+	}
+
+	for _, env := range container.Env {
+		if env.ValueFrom == nil {
+			continue
+		}
+		if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil && !isOptional(ref.Optional) {
+			refs = append(refs, reference{"ConfigMap", ref.Name})
+		}
+		if ref := env.ValueFrom.SecretKeyRef; ref != nil && !isOptional(ref.Optional) {
+			refs = append(refs, reference{"Secret", ref.Name})
+		}
+	}
+
+	for _, envFrom := range container.EnvFrom {
+		if ref := envFrom.ConfigMapRef; ref != nil && !isOptional(ref.Optional) {
+			refs = append(refs, reference{"ConfigMap", ref.Name})
+		}
+		if ref := envFrom.SecretRef; ref != nil && !isOptional(ref.Optional) {
+			refs = append(refs, reference{"Secret", ref.Name})
+		}
+	}
+
+	mounted := make(map[string]bool)
+	for _, mount := range container.VolumeMounts {
+		mounted[mount.Name] = true
+	}
+
+	for _, volume := range pod.Spec.Volumes {
+		if !mounted[volume.Name] {
+			continue
+		}
+		if vol := volume.ConfigMap; vol != nil && !isOptional(vol.Optional) {
+			refs = append(refs, reference{"ConfigMap", vol.Name})
+		}
+		if vol := volume.Secret; vol != nil && !isOptional(vol.Optional) {
+			refs = append(refs, reference{"Secret", vol.SecretName})
+		}
+		if vol := volume.Projected; vol != nil {
+			for _, source := range vol.Sources {
+				if src := source.ConfigMap; src != nil && !isOptional(src.Optional) {
+					refs = append(refs, reference{"ConfigMap", src.Name})
+				}
+				if src := source.Secret; src != nil && !isOptional(src.Optional) {
+					refs = append(refs, reference{"Secret", src.Name})
+				}
+			}
+		}
+	}
+
+	for _, ref := range refs {
+		exists, err := s.Connection.ObjectExists(ref.kind, ref.name, pod.Namespace)
+		if err != nil {
+			return missing, err
+		}
+		if !exists {
+			missing = appendUnique(missing, strings.ToLower(ref.kind)+"/"+ref.name+" not found")
+		}
+	}
+
+	return missing, nil
+}
+
+// joinEvidence joins a reason and its message, either can be empty
+func joinEvidence(reason string, message string) string {
+	if len(message) == 0 {
+		return reason
+	}
+	if len(reason) == 0 {
+		return message
+	}
+	return reason + ": " + message
+}
@@ -0,0 +1,48 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+type diagnoseContainerTest struct {
+	status   v1.ContainerStatus
+	expected []string
+}
+
+var diagnoseContainerTests = []diagnoseContainerTest{
+	{v1.ContainerStatus{Name: "web", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+		[]string{"image-pull"}},
+	{v1.ContainerStatus{Name: "web", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+		LastTerminationState: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{ExitCode: 137, Reason: "OOMKilled"}}},
+		[]string{"out-of-memory"}},
+	{v1.ContainerStatus{Name: "web", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}},
+		[]string{"crash-loop"}},
+	{v1.ContainerStatus{Name: "web", State: v1.ContainerState{Running: &v1.ContainerStateRunning{}}},
+		[]string{"not-ready"}},
+}
+
+func TestDiagnoseContainer(t *testing.T) {
+	d := diagnose{ExitCodes: exitCodeMeanings}
+	container := v1.Container{Name: "web"}
+
+	for _, test := range diagnoseContainerTests {
+		info := BuilderInformation{}
+		info.Data.pod.Status.ContainerStatuses = []v1.ContainerStatus{test.status}
+
+		list, err := d.diagnoseContainer(info, container, &test.status)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(list) != len(test.expected) {
+			t.Fatalf("Output %v not equal to expected %v", list, test.expected)
+		}
+		for i, cause := range test.expected {
+			if list[i].cause != cause {
+				t.Errorf("Output %s not equal to expected %s", list[i].cause, cause)
+			}
+		}
+	}
+}
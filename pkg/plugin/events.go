@@ -0,0 +1,215 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	duration "k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var eventsShort = "List the events of each container in a pod"
+
+var eventsDescription = ` Prints the Events of each pod alongside the container they belong to, events are matched to the
+container using their fieldPath. Each event shows its type, reason, how many times it has happened, when it was
+first and last seen and its message, the newest events are listed first.
+
+Events that belong to the pod rather than a container, such as scheduling events, are shown on the Pod row
+when using --tree. Only one event fits on the row so the newest Warning event is shown, or the newest event
+when the pod has no warnings, the COUNT is for that event only. Events are only kept by kubernetes for a
+short time, normally an hour.`
+
+var eventsExample = `  # List container events from pods in the current namespace
+  %[1]s events
+
+  # List container events from a single pod
+  %[1]s events my-pod-4jh36
+
+  # List warning events from the last 30 minutes
+  %[1]s events --warnings-only --since 30m
+
+  # List events with the pod level events shown on each pod
+  %[1]s events --tree`
+
+func Events(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Events"}
+	log.Debug("Start")
+
+	loopinfo := events{}
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	loopinfo.Connection = &connect
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+
+	if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+		return errors.New("error: events can only be shown from a live cluster")
+	}
+
+	if since := cmd.Flag("since").Value.String(); len(since) > 0 {
+		loopinfo.Since, err = time.ParseDuration(since)
+		if err != nil {
+			return fmt.Errorf("error: unable to parse --since: %w", err)
+		}
+	}
+
+	if cmd.Flag("warnings-only").Value.String() == "true" {
+		loopinfo.WarningsOnly = true
+	}
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type events struct {
+	Connection   *Connector
+	Since        time.Duration
+	WarningsOnly bool
+}
+
+func (s *events) Headers() []string {
+	return []string{
+		"TYPE", "REASON", "COUNT", "FIRST-SEEN", "LAST-SEEN", "MESSAGE",
+	}
+}
+
+func (s *events) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *events) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *events) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, 6)
+
+	if info.TypeName != TypeNamePod {
+		return rowOut, nil
+	}
+
+	// only one row fits on the pod so the newest warning is shown, this stops a FailedScheduling event
+	// being hidden by a later Scheduled event, the newest event is used when there are no warnings
+	list, err := s.podEvents(info, "")
+	if err != nil || len(list) == 0 {
+		return rowOut, err
+	}
+
+	for _, event := range list {
+		if event.Type == v1.EventTypeWarning {
+			return s.eventsBuildRow(event), nil
+		}
+	}
+
+	return s.eventsBuildRow(list[0]), nil
+}
+
+func (s *events) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return s.eventsBuildRows(info, container.Name)
+}
+
+func (s *events) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return s.eventsBuildRows(info, container.Name)
+}
+
+func (s *events) eventsBuildRows(info BuilderInformation, containerName string) ([][]Cell, error) {
+	out := [][]Cell{}
+
+	list, err := s.podEvents(info, containerName)
+	if err != nil {
+		return out, err
+	}
+
+	for _, event := range list {
+		out = append(out, s.eventsBuildRow(event))
+	}
+
+	return out, nil
+}
+
+func (s *events) eventsBuildRow(event v1.Event) []Cell {
+	count := eventCount(event)
+
+	return []Cell{
+		NewCellText(event.Type),
+		NewCellText(event.Reason),
+		NewCellInt(fmt.Sprintf("%d", count), count),
+		ageCell(eventFirstSeen(event)),
+		ageCell(eventLastSeen(event)),
+		NewCellText(event.Message),
+	}
+}
+
+// podEvents returns the filtered events of the named container newest first, an empty container name returns
+//
+//	the pod level events
+func (s *events) podEvents(info BuilderInformation, containerName string) ([]v1.Event, error) {
+	var out []v1.Event
+
+	list, err := s.Connection.GetPodEvents(info.Data.pod)
+	if err != nil {
+		return out, err
+	}
+
+	for _, event := range list {
+		if eventContainerName(event) != containerName {
+			continue
+		}
+		if s.WarningsOnly && event.Type != v1.EventTypeWarning {
+			continue
+		}
+		if s.Since > 0 && time.Since(eventLastSeen(event)) > s.Since {
+			continue
+		}
+		out = append(out, event)
+	}
+
+	sort.SliceStable(out, func(i, j int) bool {
+		return eventLastSeen(out[i]).After(eventLastSeen(out[j]))
+	})
+
+	return out, nil
+}
+
+// ageCell shows how long ago t was, the number of seconds is used for sorting
+func ageCell(t time.Time) Cell {
+	age := time.Since(t)
+	return NewCellInt(duration.HumanDuration(age), int64(age.Seconds()))
+}
@@ -0,0 +1,152 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func eventsTestEvent(fieldPath string, eventType string, reason string, count int32, age time.Duration) v1.Event {
+	return v1.Event{
+		InvolvedObject: v1.ObjectReference{FieldPath: fieldPath},
+		Type:           eventType,
+		Reason:         reason,
+		Count:          count,
+		FirstTimestamp: metav1.NewTime(time.Now().Add(-age - time.Minute)),
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-age)),
+	}
+}
+
+var eventsTestList = []v1.Event{
+	eventsTestEvent("spec.containers{web}", v1.EventTypeNormal, "Pulled", 1, 50*time.Minute),
+	eventsTestEvent("spec.containers{web}", v1.EventTypeWarning, "BackOff", 12, 2*time.Minute),
+	eventsTestEvent("spec.initContainers{setup}", v1.EventTypeNormal, "Started", 1, 55*time.Minute),
+	eventsTestEvent("spec.ephemeralContainers{debugger}", v1.EventTypeNormal, "Created", 1, 5*time.Minute),
+	eventsTestEvent("", v1.EventTypeNormal, "Scheduled", 1, 58*time.Minute),
+	eventsTestEvent("", v1.EventTypeWarning, "FailedScheduling", 4, 59*time.Minute),
+}
+
+func eventsTestInfo() BuilderInformation {
+	info := BuilderInformation{}
+	info.Data.pod.UID = "events-test"
+	return info
+}
+
+// *********
+// podEvents
+// *********
+type podEventsTest struct {
+	container    string
+	since        time.Duration
+	warningsOnly bool
+	expected     []string // reasons newest first
+}
+
+var podEventsTests = []podEventsTest{
+	// events are matched to the container using their fieldPath
+	{"web", 0, false, []string{"BackOff", "Pulled"}},
+	{"setup", 0, false, []string{"Started"}},
+	{"debugger", 0, false, []string{"Created"}},
+	{"", 0, false, []string{"Scheduled", "FailedScheduling"}},
+	{"missing", 0, false, []string{}},
+	// --since uses when the event was last seen
+	{"web", 10 * time.Minute, false, []string{"BackOff"}},
+	{"", 10 * time.Minute, false, []string{}},
+	// --warnings-only
+	{"web", 0, true, []string{"BackOff"}},
+	{"", 0, true, []string{"FailedScheduling"}},
+	{"setup", 0, true, []string{}},
+}
+
+func TestPodEvents(t *testing.T) {
+	info := eventsTestInfo()
+
+	for _, test := range podEventsTests {
+		s := events{
+			Connection:   &Connector{eventList: map[string][]v1.Event{"events-test": eventsTestList}},
+			Since:        test.since,
+			WarningsOnly: test.warningsOnly,
+		}
+
+		list, err := s.podEvents(info, test.container)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(list) != len(test.expected) {
+			t.Errorf("Output %d events not equal to expected %v for %s", len(list), test.expected, test.container)
+			continue
+		}
+		for i, reason := range test.expected {
+			if list[i].Reason != reason {
+				t.Errorf("Output %s not equal to expected %s", list[i].Reason, reason)
+			}
+		}
+	}
+
+}
+
+// ******************
+// events BuildBranch
+// ******************
+func TestEventsBuildBranch(t *testing.T) {
+	s := events{Connection: &Connector{eventList: map[string][]v1.Event{"events-test": eventsTestList}}}
+
+	info := eventsTestInfo()
+	info.TypeName = TypeNamePod
+
+	row, err := s.BuildBranch(info, [][]Cell{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the pod row is the newest pod level warning with its own count
+	if row[1].text != "FailedScheduling" || row[2].number != 4 {
+		t.Errorf("Output %s %d not equal to expected FailedScheduling 4", row[1].text, row[2].number)
+	}
+
+	// the newest event is used when there are no warnings
+	s.Connection.eventList["events-test"] = eventsTestList[:5]
+	row, _ = s.BuildBranch(info, [][]Cell{})
+	if row[1].text != "Scheduled" || row[2].number != 1 {
+		t.Errorf("Output %s %d not equal to expected Scheduled 1", row[1].text, row[2].number)
+	}
+
+	info.TypeName = TypeNameDeployment
+	if row, _ := s.BuildBranch(info, [][]Cell{}); len(row[1].text) > 0 {
+		t.Errorf("Output %s not equal to expected an empty row", row[1].text)
+	}
+
+}
+
+// **************
+// eventFirstSeen
+// **************
+type eventFirstSeenTest struct {
+	arg1     v1.Event
+	expected time.Time
+}
+
+var eventFirstSeenTests = []eventFirstSeenTest{
+	{v1.Event{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(eventTestTime)}}, eventTestTime},
+	{v1.Event{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(eventTestTime)},
+		EventTime:  metav1.NewMicroTime(eventTestTime.Add(time.Minute)),
+	}, eventTestTime.Add(time.Minute)},
+	{v1.Event{
+		EventTime:      metav1.NewMicroTime(eventTestTime.Add(time.Minute)),
+		FirstTimestamp: metav1.NewTime(eventTestTime.Add(-time.Minute)),
+	}, eventTestTime.Add(-time.Minute)},
+}
+
+func TestEventFirstSeen(t *testing.T) {
+
+	for _, test := range eventFirstSeenTests {
+		if output := eventFirstSeen(test.arg1); !output.Equal(test.expected) {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+
+}
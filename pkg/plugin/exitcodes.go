@@ -0,0 +1,89 @@
+package plugin
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/spf13/viper"
+)
+
+// exitCodeMeanings decodes the exit codes that are commonly seen, codes above 128 are normally the process
+//
+//	being killed by signal (code - 128), exit code 0 is left out as there is nothing to explain
+var exitCodeMeanings = map[int32]string{
+	1:   "general error, also used by java for an uncaught exception",
+	2:   "misuse of a shell builtin, also used by go for a runtime panic",
+	126: "command found but is not executable",
+	127: "command not found",
+	128: "invalid argument to exit",
+	134: "SIGABRT, the process aborted, also used by the jvm for a fatal error",
+	137: "SIGKILL, killed by the kubelet or runtime, check for a failed liveness probe",
+	139: "SIGSEGV, segmentation fault",
+	143: "SIGTERM, asked to stop by the kubelet, the pod was deleted or failed a probe",
+	255: "exit code out of range",
+}
+
+// signalNames are the linux signals that can terminate a container
+var signalNames = map[int32]string{
+	1:  "SIGHUP",
+	2:  "SIGINT",
+	3:  "SIGQUIT",
+	4:  "SIGILL",
+	5:  "SIGTRAP",
+	6:  "SIGABRT",
+	7:  "SIGBUS",
+	8:  "SIGFPE",
+	9:  "SIGKILL",
+	10: "SIGUSR1",
+	11: "SIGSEGV",
+	12: "SIGUSR2",
+	13: "SIGPIPE",
+	14: "SIGALRM",
+	15: "SIGTERM",
+}
+
+// loadExitCodeMeanings returns the built in exit code meanings along with any set under exitCodes in the
+//
+//	config file, the config file takes priority
+func loadExitCodeMeanings() (map[int32]string, error) {
+	meanings := make(map[int32]string)
+	for code, meaning := range exitCodeMeanings {
+		meanings[code] = meaning
+	}
+
+	for key, meaning := range viper.GetStringMapString("exitCodes") {
+		code, err := strconv.ParseInt(key, 10, 32)
+		if err != nil {
+			return meanings, fmt.Errorf("error: invalid exit code %q in config file", key)
+		}
+		meanings[int32(code)] = meaning
+	}
+
+	return meanings, nil
+}
+
+// exitCodeMeaning decodes the exit code and signal of a terminated container, reason is checked so an OOM
+//
+//	kill can be told apart from other SIGKILLs
+func exitCodeMeaning(meanings map[int32]string, exitCode int32, signal int32, reason string) string {
+	if exitCode == 137 && reason == "OOMKilled" {
+		return "OOMKilled, the container used more memory than its limit"
+	}
+
+	if meaning, ok := meanings[exitCode]; ok {
+		return meaning
+	}
+
+	if signal == 0 && exitCode > 128 && exitCode <= 128+64 {
+		signal = exitCode - 128
+	}
+
+	if signal > 0 {
+		if name, ok := signalNames[signal]; ok {
+			return fmt.Sprintf("%s, killed by signal %d", name, signal)
+		}
+		return fmt.Sprintf("killed by signal %d", signal)
+	}
+
+	return ""
+}
@@ -0,0 +1,88 @@
+package plugin
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spf13/viper"
+)
+
+type exitCodeMeaningTest struct {
+	exitCode int32
+	signal   int32
+	reason   string
+	expected string
+}
+
+var exitCodeMeaningTests = []exitCodeMeaningTest{
+	{137, 0, "OOMKilled", "OOMKilled, the container used more memory than its limit"},
+	{137, 0, "Error", exitCodeMeanings[137]},
+	{143, 0, "Error", exitCodeMeanings[143]},
+	{127, 0, "Error", exitCodeMeanings[127]},
+	{130, 0, "Error", "SIGINT, killed by signal 2"},
+	{0, 0, "Completed", ""},
+	{0, 9, "Error", "SIGKILL, killed by signal 9"},
+	{42, 0, "Error", ""},
+	{42, 40, "Error", "killed by signal 40"},
+}
+
+func TestExitCodeMeaning(t *testing.T) {
+
+	for _, test := range exitCodeMeaningTests {
+		if output := exitCodeMeaning(exitCodeMeanings, test.exitCode, test.signal, test.reason); output != test.expected {
+			t.Errorf("exit code %d: output %q not equal to expected %q", test.exitCode, output, test.expected)
+		}
+	}
+
+}
+
+// ********************
+// loadExitCodeMeanings
+// ********************
+type loadExitCodeMeaningsTest struct {
+	config   string
+	expected map[int32]string
+	err      bool
+}
+
+var loadExitCodeMeaningsTests = []loadExitCodeMeaningsTest{
+	{"", map[int32]string{1: exitCodeMeanings[1], 137: exitCodeMeanings[137]}, false},
+	// numeric yaml keys are read as strings by viper
+	{"exitCodes:\n  3: config file missing\n  137: killed\n", map[int32]string{
+		1:   exitCodeMeanings[1],
+		3:   "config file missing",
+		137: "killed",
+	}, false},
+	{"exitCodes:\n  \"64\": usage error\n", map[int32]string{64: "usage error"}, false},
+	{"exitCodes:\n  abc: not a number\n", nil, true},
+}
+
+func TestLoadExitCodeMeanings(t *testing.T) {
+	defer viper.Reset()
+
+	for _, test := range loadExitCodeMeaningsTests {
+		viper.Reset()
+		viper.SetConfigType("yaml")
+		if err := viper.ReadConfig(strings.NewReader(test.config)); err != nil {
+			t.Fatal(err)
+		}
+
+		meanings, err := loadExitCodeMeanings()
+		if test.err {
+			if err == nil {
+				t.Errorf("expected an error for config %q", test.config)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for code, expected := range test.expected {
+			if meanings[code] != expected {
+				t.Errorf("exit code %d: output %q not equal to expected %q", code, meanings[code], expected)
+			}
+		}
+	}
+
+}
@@ -0,0 +1,223 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var hostShort = "Shows host namespaces, host ports and hostPath volumes used by each container"
+
+var hostDescription = ` Prints the host access that each container has been given, this includes the pod level hostNetwork,
+hostPID, hostIPC and shareProcessNamespace settings along with any host ports the container exposes and the
+hostPath volumes it mounts. hostPath volumes are shown as path:type, the type is left off when not set.
+
+The HOST_ACCESS column is true when the container has any of the above. In tree view each owner is
+marked as having host access when any of the containers below it do.`
+
+var hostExample = `  # List host access of all containers in the current namespace
+  %[1]s host
+
+  # List host access of containers in a single pod
+  %[1]s host my-pod-4jh36
+
+  # List only containers that have some form of host access
+  %[1]s host -m "HOST_ACCESS=true"
+
+  # Show which deployments, daemonsets and statefulsets have host access
+  %[1]s host -A --tree`
+
+func Host(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Host"}
+	log.Debug("Start")
+
+	loopinfo := host{}
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type host struct {
+}
+
+func (s *host) Headers() []string {
+	return []string{
+		"HOST_NETWORK", "HOST_PID", "HOST_IPC", "SHARE_PROCESS_NS", "HOST_PORTS", "HOST_PATHS", "HOST_ACCESS",
+	}
+}
+
+func (s *host) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *host) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *host) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	var ports, paths []string
+
+	// "HOST_NETWORK", "HOST_PID", "HOST_IPC", "SHARE_PROCESS_NS", "HOST_PORTS", "HOST_PATHS", "HOST_ACCESS"
+	flags := make([]bool, 4)
+	access := false
+	for _, r := range rows {
+		for i := range flags {
+			flags[i] = flags[i] || r[i].text == "true"
+		}
+		ports = appendUnique(ports, r[4].text)
+		paths = appendUnique(paths, r[5].text)
+		access = access || r[6].text == "true"
+	}
+
+	if info.TypeName == TypeNamePod {
+		// the pod can have hostPath volumes that none of its containers mount
+		paths = []string{}
+		for _, path := range s.hostPaths(info.Data.pod.Spec.Volumes, nil, false) {
+			paths = appendUnique(paths, path)
+		}
+		access = access || len(paths) > 0
+	}
+
+	rowOut := make([]Cell, 7)
+	for i, flag := range flags {
+		rowOut[i] = NewCellText(fmt.Sprintf("%t", flag))
+	}
+	rowOut[4] = NewCellText(strings.Join(ports, ","))
+	rowOut[5] = NewCellText(strings.Join(paths, ","))
+	rowOut[6] = NewCellText(fmt.Sprintf("%t", access))
+
+	return rowOut, nil
+}
+
+func (s *host) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	out := make([][]Cell, 1)
+	out[0] = s.hostBuildRow(info, container.Ports, container.VolumeMounts)
+	return out, nil
+}
+
+func (s *host) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	out := make([][]Cell, 1)
+	out[0] = s.hostBuildRow(info, container.Ports, container.VolumeMounts)
+	return out, nil
+}
+
+func (s *host) hostBuildRow(info BuilderInformation, containerPorts []v1.ContainerPort, mounts []v1.VolumeMount) []Cell {
+	var hostPorts []string
+
+	spec := info.Data.pod.Spec
+
+	hostNetwork := spec.HostNetwork
+	hostPID := spec.HostPID
+	hostIPC := spec.HostIPC
+	shareProcess := spec.ShareProcessNamespace != nil && *spec.ShareProcessNamespace
+
+	for _, port := range containerPorts {
+		if port.HostPort > 0 {
+			hostPorts = append(hostPorts, fmt.Sprintf("%d/%s", port.HostPort, port.Protocol))
+		}
+	}
+
+	hostPaths := s.hostPaths(spec.Volumes, mounts, true)
+
+	access := hostNetwork || hostPID || hostIPC || shareProcess || len(hostPorts) > 0 || len(hostPaths) > 0
+
+	return []Cell{
+		NewCellText(fmt.Sprintf("%t", hostNetwork)),
+		NewCellText(fmt.Sprintf("%t", hostPID)),
+		NewCellText(fmt.Sprintf("%t", hostIPC)),
+		NewCellText(fmt.Sprintf("%t", shareProcess)),
+		NewCellText(strings.Join(hostPorts, ",")),
+		NewCellText(strings.Join(hostPaths, ",")),
+		NewCellText(fmt.Sprintf("%t", access)),
+	}
+}
+
+// hostPaths returns the path:type of each hostPath volume, when onlyMounted is set only the volumes that are
+//
+//	listed in mounts are returned
+func (s *host) hostPaths(podVolumes []v1.Volume, mounts []v1.VolumeMount, onlyMounted bool) []string {
+	var out []string
+
+	vol := volumes{}
+	volumeMap := vol.createVolumeMap(podVolumes)
+
+	mounted := make(map[string]bool)
+	for _, mount := range mounts {
+		mounted[mount.Name] = true
+	}
+
+	for _, volume := range podVolumes {
+		if volume.HostPath == nil {
+			continue
+		}
+		if onlyMounted && !mounted[volume.Name] {
+			continue
+		}
+
+		path := volumeMap[volume.Name]["backing"].text
+		if volume.HostPath.Type != nil && len(*volume.HostPath.Type) > 0 {
+			path += ":" + string(*volume.HostPath.Type)
+		}
+		out = append(out, path)
+	}
+
+	sort.Strings(out)
+	return out
+}
+
+// appendUnique splits a comma seperated list and appends each value not already in list
+func appendUnique(list []string, values string) []string {
+	if len(values) == 0 {
+		return list
+	}
+
+	for _, value := range strings.Split(values, ",") {
+		found := false
+		for _, item := range list {
+			if item == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+
+	return list
+}
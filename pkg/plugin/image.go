@@ -1,6 +1,8 @@
 package plugin
 
 import (
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,6 +16,22 @@ var imageDescription = ` Print the the image used for running containers in a po
 and containers can be selected by name. If no name is specified the image details of all pods in
 the current namespace are shown.
 
+The image is also split into its REGISTRY, REPOSITORY, TAG and DIGEST, images without a registry are shown
+as docker.io and official docker hub images are placed under library/. DIGEST_MISMATCH is true when an image
+is pinned to a digest that is different to the digest of the image that is running.
+
+Use --running to also show the image the container is actually running, this can be different to the
+spec when the image has been changed in place, DRIFT is true when the running image is not the one in the
+spec.
+
+Use --drift to find pods of the same workload that are running different images, containers are grouped by
+their owning workload and container name showing how many tags and digests are running. NOT_MAJORITY lists
+the pods that are not running the most common digest and NOT_TEMPLATE the pods that are not running the
+image in the owners pod template. --drift=repository instead lists every repository in a namespace that is
+running more than one version along with the workloads running each version. Init containers are included
+in both, containers whose runtime only reports a local image id have no digest to compare so they are not
+counted as running a different digest.
+
 The T column in the table output denotes S for Standard and I for init containers`
 
 var imageExample = `  # List containers image info from pods
@@ -41,7 +59,19 @@ var imageExample = `  # List containers image info from pods
   %[1]s image -l app=web
 
   # List container image info from all pods where the pod label app is either web or mail
-  %[1]s image -l "app in (web,mail)"`
+  %[1]s image -l "app in (web,mail)"
+
+  # List containers that are not running the image in their spec
+  %[1]s image --running --match DRIFT=true
+
+  # List the deployments, statefulsets and daemonsets running more than one image digest
+  %[1]s image --drift --match 'DIGESTS>1'
+
+  # List every repository that is running at several versions in all namespaces
+  %[1]s image -A --drift=repository
+
+  # List containers using images that are not from our.registry.io
+  %[1]s image --match REGISTRY!=our.registry.io`
 
 func Image(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
 	log := logger{location: "Image"}
@@ -70,6 +100,21 @@ func Image(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []
 		loopinfo.ShowID = true
 	}
 
+	if cmd.Flag("running").Value.String() == "true" {
+		loopinfo.ShowRunning = true
+	}
+
+	if drift := cmd.Flag("drift").Value.String(); len(drift) > 0 {
+		stdinChanged, err := builder.HasStdinChanged()
+		if err != nil {
+			return err
+		}
+		if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+			return errors.New("error: image drift needs the owner of each pod and can only be shown from a live cluster")
+		}
+		return imageDrift(&connect, commonFlagList, args, drift)
+	}
+
 	table := Table{}
 	builder.Table = &table
 	builder.CommonFlags = commonFlagList
@@ -89,12 +134,14 @@ func Image(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []
 }
 
 type image struct {
-	ShowID bool
+	ShowID      bool
+	ShowRunning bool
 }
 
 func (s *image) Headers() []string {
 	return []string{
-		"PULL", "IMAGEID", "CONTAINERID", "IMAGE", "TAG",
+		"PULL", "IMAGEID", "CONTAINERID", "IMAGE", "TAG", "REGISTRY", "REPOSITORY", "DIGEST", "DIGEST_MISMATCH",
+		"RUNNING_IMAGE", "DRIFT",
 	}
 }
 
@@ -113,6 +160,10 @@ func (s *image) HideColumns(info BuilderInformation) []int {
 		hideColumns = append(hideColumns, 1, 2)
 	}
 
+	if !s.ShowRunning {
+		hideColumns = append(hideColumns, 9, 10)
+	}
+
 	return hideColumns
 }
 
@@ -138,50 +189,26 @@ func (s *image) imageBuildRow(info BuilderInformation, imageName string, pullPol
 	var imageID string
 	var containerID string
 	var cellList []Cell
+	var runningImage string
+	var mismatch Cell
+	var drift Cell
 
-	name := imageName
-	tag := ""
-
-	if strings.Contains(imageName, "/") {
-		arrPath := strings.Split(imageName, "/")
-		if c := len(arrPath); c > 0 {
-			tmp := strings.Split(arrPath[c-1], ":")
-			if len(tmp) > 0 {
-				tag = strings.Join(tmp[1:], ":")
-				//calculate the uri length
-				namelen := len(imageName) - len(tag)
-				if len(tag) > 0 {
-					// check a tag was supplied so we dont cut off the last char of the image name
-					namelen--
-				}
-				name = imageName[0:namelen]
-			}
-		}
-	} else {
-		arrImage := strings.Split(imageName, ":")
-		if c := len(arrImage); c > 0 {
-			tag = arrImage[c-1]
-			name = strings.Join(arrImage[:c-1], ":")
-		}
-	}
+	ref := parseImageReference(imageName)
 
-	for _, status := range info.Data.pod.Status.InitContainerStatuses {
-		if status.Image == imageName {
-			imageID = status.ImageID
-			containerID = status.ContainerID
-		}
+	if status := containerStatusByName(info.Data.pod, info.Name); status != nil {
+		imageID = status.ImageID
+		containerID = status.ContainerID
+		runningImage = status.Image
 	}
-	for _, status := range info.Data.pod.Status.ContainerStatuses {
-		if status.Image == imageName {
-			imageID = status.ImageID
-			containerID = status.ContainerID
-		}
+
+	// only pinned images that are running from a known repo digest can be checked
+	digest, ok := imageRepoDigest(imageID)
+	if len(ref.Digest) > 0 && ok {
+		mismatch = NewCellText(fmt.Sprintf("%t", ref.Digest != digest))
 	}
-	for _, status := range info.Data.pod.Status.EphemeralContainerStatuses {
-		if status.Image == imageName {
-			imageID = status.ImageID
-			containerID = status.ContainerID
-		}
+
+	if len(runningImage) > 0 {
+		drift = NewCellText(fmt.Sprintf("%t", imageDrifted(ref, runningImage, imageID)))
 	}
 
 	if val := strings.Split(imageID, "@"); len(val) == 2 {
@@ -192,9 +219,103 @@ func (s *image) imageBuildRow(info BuilderInformation, imageName string, pullPol
 		NewCellText(pullPolicy),
 		NewCellText(imageID),
 		NewCellText(containerID),
-		NewCellText(name),
-		NewCellText(tag),
+		NewCellText(ref.Name),
+		NewCellText(ref.Tag),
+		NewCellText(ref.Registry),
+		NewCellText(ref.Repository),
+		NewCellText(ref.Digest),
+		mismatch,
+		NewCellText(runningImage),
+		drift,
 	)
 
 	return cellList
 }
+
+// imageReference is an image name split into its parts, Name is the image as written without the tag or digest
+type imageReference struct {
+	Name       string
+	Registry   string
+	Repository string
+	Tag        string
+	Digest     string
+}
+
+// parseImageReference splits an image into its registry, repository, tag and digest, images without a
+//
+//	registry are from docker.io and single name docker.io images are placed in library/, the tag defaults
+//	to latest when neither a tag or digest is given
+func parseImageReference(imageName string) imageReference {
+	ref := imageReference{}
+
+	name := imageName
+	if i := strings.Index(name, "@"); i >= 0 {
+		ref.Digest = name[i+1:]
+		name = name[:i]
+	}
+
+	// a colon after the last slash is the tag, before it would be a registry port
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		ref.Tag = name[i+1:]
+		name = name[:i]
+	}
+	ref.Name = name
+
+	repository := name
+	if i := strings.Index(name, "/"); i >= 0 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" || strings.ToLower(first) != first {
+			ref.Registry = first
+			repository = name[i+1:]
+		}
+	}
+
+	switch ref.Registry {
+	case "", "index.docker.io", "registry-1.docker.io":
+		ref.Registry = "docker.io"
+	}
+
+	if ref.Registry == "docker.io" && !strings.Contains(repository, "/") {
+		repository = "library/" + repository
+	}
+	ref.Repository = repository
+
+	if len(ref.Tag) == 0 && len(ref.Digest) == 0 {
+		ref.Tag = "latest"
+	}
+
+	return ref
+}
+
+// imageRepoDigest returns the digest part of an image id in the form name@sha256:..., false is returned
+//
+//	when the image id is a local image id such as sha256:... as that can not be compared with a repo digest
+func imageRepoDigest(imageID string) (string, bool) {
+	i := strings.LastIndex(imageID, "@")
+	if i < 0 || !strings.HasPrefix(imageID[i+1:], "sha256:") {
+		return "", false
+	}
+	return imageID[i+1:], true
+}
+
+// imageDrifted checks if the image reported by the container status is different to the image in the spec,
+//
+//	pinned images are compared by digest everything else by registry, repository and tag
+func imageDrifted(spec imageReference, runningImage string, imageID string) bool {
+	if len(spec.Digest) > 0 {
+		digest, ok := imageRepoDigest(imageID)
+		if !ok {
+			// we dont know which digest is running
+			return false
+		}
+		return spec.Digest != digest
+	}
+
+	if strings.HasPrefix(runningImage, "sha256:") {
+		// some runtimes only report the image id, so there is nothing to compare the tag with
+		return false
+	}
+
+	running := parseImageReference(runningImage)
+	return spec.Registry != running.Registry || spec.Repository != running.Repository || spec.Tag != running.Tag
+}
@@ -0,0 +1,347 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// runningImage is the image a single container in a pod is running
+type runningImage struct {
+	podName   string
+	namespace string
+	container string
+	workload  string
+	ref       imageReference
+	tag       string
+	digest    string
+	image     string
+	imageID   string
+	template  *imageReference
+}
+
+// driftGroup is a set of containers that should all be running the same image
+type driftGroup struct {
+	namespace string
+	name      string
+	container string
+	template  string
+	running   []runningImage
+}
+
+// imageDrift prints the image drift table, mode is either workload or repository
+func imageDrift(connect *Connector, commonFlagList commonFlags, podNames []string, mode string) error {
+	log := logger{location: "imageDrift"}
+	log.Debug("Start")
+
+	if _, err := connect.GetPods(podNames); err != nil {
+		return err
+	}
+
+	images := []runningImage{}
+	for _, root := range connect.BuildOwnersList() {
+		images = append(images, collectRunningImages(root, nil)...)
+	}
+
+	var header []string
+	var rows [][]Cell
+
+	switch mode {
+	case "workload":
+		header = []string{
+			"NAMESPACE", "WORKLOAD", "CONTAINER", "PODS", "TAGS", "DIGESTS", "TEMPLATE", "MAJORITY", "NOT_MAJORITY", "NOT_TEMPLATE",
+		}
+		for _, group := range groupByWorkload(images) {
+			rows = append(rows, group.workloadCells())
+		}
+
+	case "repository":
+		header = []string{
+			"NAMESPACE", "REPOSITORY", "TAG", "DIGEST", "PODS", "WORKLOADS",
+		}
+		for _, group := range groupByRepository(images) {
+			rows = append(rows, group.repositoryRows()...)
+		}
+
+	default:
+		return errors.New("error: --drift must be either workload or repository")
+	}
+
+	table := Table{}
+	table.SetHeader(header...)
+
+	// the drift table is not made by the row builder so the match filter is applied here
+	filter := RowBuilder{FilterList: commonFlagList.filterList}
+	if err := filter.setTableFilter(header); err != nil {
+		return err
+	}
+
+	for _, row := range rows {
+		if filter.matchShouldExclude(row) {
+			continue
+		}
+		table.AddRow(row...)
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+}
+
+// collectRunningImages walks the owner tree recording the image each container is running, the first owner
+//
+//	below the node is used as the workload, pods without an owner are their own workload
+func collectRunningImages(leaf *LeafNode, workload *ParentData) []runningImage {
+	var out []runningImage
+
+	if leaf.kind != TypeNameNode && leaf.kind != TypeNamePod && workload == nil {
+		data := leaf.data
+		workload = &data
+	}
+
+	if leaf.kind != TypeNamePod {
+		for _, child := range leaf.child {
+			out = append(out, collectRunningImages(child, workload)...)
+		}
+		return out
+	}
+
+	pod := leaf.data.pod
+	workloadName := TypeNamePod + "/" + pod.Name
+	var template *v1.PodTemplateSpec
+	if workload != nil {
+		workloadName = workload.kind + "/" + workload.name
+		template = ownerTemplate(*workload)
+	}
+
+	// init containers are checked as well, names are unique across both lists
+	containers := append(append([]v1.Container{}, pod.Spec.InitContainers...), pod.Spec.Containers...)
+	var templateContainers []v1.Container
+	if template != nil {
+		templateContainers = append(append([]v1.Container{}, template.Spec.InitContainers...), template.Spec.Containers...)
+	}
+
+	for _, container := range containers {
+		status := containerStatusByName(pod, container.Name)
+		if status == nil || len(status.ImageID) == 0 {
+			// the container hasnt started so nothing is running
+			continue
+		}
+
+		running := runningImage{
+			podName:   pod.Name,
+			namespace: pod.Namespace,
+			container: container.Name,
+			workload:  workloadName,
+			image:     status.Image,
+			imageID:   status.ImageID,
+		}
+
+		// local image ids cant be compared with repo digests so the digest is left empty
+		running.digest, _ = imageRepoDigest(status.ImageID)
+
+		// some runtimes only report the image id so fall back to the spec for the name and tag
+		if strings.HasPrefix(status.Image, "sha256:") {
+			running.ref = parseImageReference(container.Image)
+		} else {
+			running.ref = parseImageReference(status.Image)
+		}
+		running.tag = running.ref.Tag
+
+		for _, c := range templateContainers {
+			if c.Name == container.Name {
+				ref := parseImageReference(c.Image)
+				running.template = &ref
+			}
+		}
+
+		out = append(out, running)
+	}
+
+	return out
+}
+
+// ownerTemplate returns the pod template of a workload, nil is returned for kinds without a template
+func ownerTemplate(owner ParentData) *v1.PodTemplateSpec {
+	switch owner.kind {
+	case TypeNameDeployment:
+		return &owner.deployment.Spec.Template
+	case TypeNameReplicaSet:
+		return &owner.replica.Spec.Template
+	case TypeNameStatefulSet:
+		return &owner.stateful.Spec.Template
+	case TypeNameDaemonSet:
+		return &owner.daemon.Spec.Template
+	case TypeNameJob:
+		return &owner.job.Spec.Template
+	case TypeNameCronJob:
+		return &owner.cronjob.Spec.JobTemplate.Spec.Template
+	}
+
+	return nil
+}
+
+// groupByWorkload groups the running images by namespace, workload and container name
+func groupByWorkload(images []runningImage) []*driftGroup {
+	return groupImages(images, func(r runningImage) (string, string) {
+		return r.workload, r.container
+	})
+}
+
+// groupByRepository groups the running images by namespace and repository
+func groupByRepository(images []runningImage) []*driftGroup {
+	return groupImages(images, func(r runningImage) (string, string) {
+		return r.ref.Registry + "/" + r.ref.Repository, ""
+	})
+}
+
+// groupImages groups the running images by namespace and the name and container returned by key
+func groupImages(images []runningImage, key func(runningImage) (string, string)) []*driftGroup {
+	var out []*driftGroup
+
+	groups := make(map[string]*driftGroup)
+	for _, r := range images {
+		name, container := key(r)
+		id := r.namespace + "/" + name + "/" + container
+
+		group, ok := groups[id]
+		if !ok {
+			group = &driftGroup{namespace: r.namespace, name: name, container: container}
+			groups[id] = group
+			out = append(out, group)
+		}
+		if r.template != nil {
+			group.template = imageReferenceString(*r.template)
+		}
+		group.running = append(group.running, r)
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		a := out[i].namespace + "/" + out[i].name + "/" + out[i].container
+		b := out[j].namespace + "/" + out[j].name + "/" + out[j].container
+		return a < b
+	})
+
+	return out
+}
+
+// workloadCells summarises the images running in a workload
+func (g *driftGroup) workloadCells() []Cell {
+	tags := map[string]int{}
+	digests := map[string]int{}
+	for _, r := range g.running {
+		tags[r.tag]++
+		if len(r.digest) > 0 {
+			digests[r.digest]++
+		}
+	}
+
+	majority := mostCommon(digests)
+
+	notMajority := []string{}
+	notTemplate := []string{}
+	for _, r := range g.running {
+		if len(r.digest) > 0 && r.digest != majority {
+			notMajority = append(notMajority, r.podName)
+		}
+		if r.template != nil && imageDrifted(*r.template, r.image, r.imageID) {
+			notTemplate = append(notTemplate, r.podName)
+		}
+	}
+
+	return []Cell{
+		NewCellText(g.namespace),
+		NewCellText(g.name),
+		NewCellText(g.container),
+		NewCellInt(fmt.Sprintf("%d", len(g.running)), int64(len(g.running))),
+		NewCellInt(fmt.Sprintf("%d", len(tags)), int64(len(tags))),
+		NewCellInt(fmt.Sprintf("%d", len(digests)), int64(len(digests))),
+		NewCellText(g.template),
+		NewCellText(majority),
+		NewCellText(strings.Join(notMajority, ",")),
+		NewCellText(strings.Join(notTemplate, ",")),
+	}
+}
+
+// repositoryRows returns a row for each version of the repository that is running, nothing is returned when
+//
+//	only a single version is running
+func (g *driftGroup) repositoryRows() [][]Cell {
+	var out [][]Cell
+
+	versions := map[string][]runningImage{}
+	names := []string{}
+	for _, r := range g.running {
+		if len(r.digest) == 0 {
+			// the running digest is unknown so it cant be compared
+			continue
+		}
+		version := r.tag + "@" + r.digest
+		if _, ok := versions[version]; !ok {
+			names = append(names, version)
+		}
+		versions[version] = append(versions[version], r)
+	}
+
+	if len(versions) < 2 {
+		return out
+	}
+
+	sort.Strings(names)
+	for _, version := range names {
+		list := versions[version]
+
+		workloads := []string{}
+		for _, r := range list {
+			workloads = appendUnique(workloads, r.workload)
+		}
+
+		out = append(out, []Cell{
+			NewCellText(g.namespace),
+			NewCellText(g.name),
+			NewCellText(list[0].tag),
+			NewCellText(list[0].digest),
+			NewCellInt(fmt.Sprintf("%d", len(list)), int64(len(list))),
+			NewCellText(strings.Join(workloads, ",")),
+		})
+	}
+
+	return out
+}
+
+// mostCommon returns the key with the highest count, ties go to the first key in sort order
+func mostCommon(counts map[string]int) string {
+	keys := []string{}
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	best := ""
+	bestCount := 0
+	for _, key := range keys {
+		if counts[key] > bestCount {
+			best = key
+			bestCount = counts[key]
+		}
+	}
+	return best
+}
+
+// imageReferenceString joins a parsed image back into a single string
+func imageReferenceString(ref imageReference) string {
+	out := ref.Registry + "/" + ref.Repository
+	if len(ref.Tag) > 0 {
+		out += ":" + ref.Tag
+	}
+	if len(ref.Digest) > 0 {
+		out += "@" + ref.Digest
+	}
+	return out
+}
@@ -0,0 +1,156 @@
+package plugin
+
+import (
+	"testing"
+
+	a1 "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// *******************
+// parseImageReference
+// *******************
+type parseImageReferenceTest struct {
+	arg1     string
+	expected imageReference
+}
+
+var parseImageReferenceTests = []parseImageReferenceTest{
+	{"nginx", imageReference{"nginx", "docker.io", "library/nginx", "latest", ""}},
+	{"nginx:1.21", imageReference{"nginx", "docker.io", "library/nginx", "1.21", ""}},
+	{"bitnami/redis:7.0", imageReference{"bitnami/redis", "docker.io", "bitnami/redis", "7.0", ""}},
+	{"index.docker.io/library/busybox", imageReference{"index.docker.io/library/busybox", "docker.io", "library/busybox", "latest", ""}},
+	{"registry:5000/app", imageReference{"registry:5000/app", "registry:5000", "app", "latest", ""}},
+	{"registry:5000/team/app:v2", imageReference{"registry:5000/team/app", "registry:5000", "team/app", "v2", ""}},
+	{"localhost/app:dev", imageReference{"localhost/app", "localhost", "app", "dev", ""}},
+	{"quay.io/prometheus/node-exporter@sha256:abc123", imageReference{"quay.io/prometheus/node-exporter", "quay.io", "prometheus/node-exporter", "", "sha256:abc123"}},
+	{"gcr.io/proj/app:1.0@sha256:abc123", imageReference{"gcr.io/proj/app", "gcr.io", "proj/app", "1.0", "sha256:abc123"}},
+}
+
+func TestParseImageReference(t *testing.T) {
+
+	for _, test := range parseImageReferenceTests {
+		if output := parseImageReference(test.arg1); output != test.expected {
+			t.Errorf("Output %+v not equal to expected %+v", output, test.expected)
+		}
+	}
+
+}
+
+// ************
+// imageDrifted
+// ************
+type imageDriftedTest struct {
+	spec     string
+	running  string
+	imageID  string
+	expected bool
+}
+
+var imageDriftedTests = []imageDriftedTest{
+	{"nginx@sha256:1111", "nginx@sha256:1111", "docker-pullable://nginx@sha256:1111", false},
+	{"nginx@sha256:1111", "nginx@sha256:1111", "docker.io/library/nginx@sha256:2222", true},
+	// a local image id is not a repo digest so can not be compared
+	{"nginx@sha256:1111", "nginx@sha256:1111", "sha256:2222", false},
+	{"nginx@sha256:1111", "nginx@sha256:1111", "docker://sha256:2222", false},
+	{"nginx:1.21", "docker.io/library/nginx:1.21", "sha256:2222", false},
+	{"nginx:1.21", "docker.io/library/nginx:1.20", "sha256:2222", true},
+	{"nginx:1.21", "sha256:2222", "sha256:2222", false},
+}
+
+func TestImageDrifted(t *testing.T) {
+
+	for _, test := range imageDriftedTests {
+		if output := imageDrifted(parseImageReference(test.spec), test.running, test.imageID); output != test.expected {
+			t.Errorf("Output %t not equal to expected %t for %s running %s", output, test.expected, test.spec, test.imageID)
+		}
+	}
+
+}
+
+// ********************
+// collectRunningImages
+// ********************
+func driftPod(name string, image string, digest string) *LeafNode {
+	pod := v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "nginx:1.21"}}},
+		Status: v1.PodStatus{ContainerStatuses: []v1.ContainerStatus{
+			{Name: "app", Image: image, ImageID: "docker.io/library/nginx@" + digest},
+		}},
+	}
+	return &LeafNode{name: name, kind: TypeNamePod, data: ParentData{kind: TypeNamePod, pod: pod}}
+}
+
+func TestImageDriftWorkload(t *testing.T) {
+	deployment := a1.Deployment{Spec: a1.DeploymentSpec{Template: v1.PodTemplateSpec{
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", Image: "nginx:1.21"}}},
+	}}}
+
+	replica := &LeafNode{name: "web-1", kind: TypeNameReplicaSet, data: ParentData{kind: TypeNameReplicaSet}, child: []*LeafNode{
+		driftPod("web-1-a", "docker.io/library/nginx:1.21", "sha256:1111"),
+		driftPod("web-1-b", "docker.io/library/nginx:1.21", "sha256:1111"),
+		driftPod("web-1-c", "docker.io/library/nginx:1.20", "sha256:2222"),
+	}}
+	root := &LeafNode{name: "node-1", kind: TypeNameNode, child: []*LeafNode{
+		{name: "web", kind: TypeNameDeployment, data: ParentData{name: "web", kind: TypeNameDeployment, deployment: deployment}, child: []*LeafNode{replica}},
+	}}
+
+	groups := groupByWorkload(collectRunningImages(root, nil))
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group got %d", len(groups))
+	}
+
+	cells := groups[0].workloadCells()
+	expected := []string{"default", "Deployment/web", "app", "3", "2", "2", "docker.io/library/nginx:1.21", "sha256:1111", "web-1-c", "web-1-c"}
+	for i, value := range expected {
+		if cells[i].text != value {
+			t.Errorf("column %d output %s not equal to expected %s", i, cells[i].text, value)
+		}
+	}
+}
+
+func TestImageDriftInitContainers(t *testing.T) {
+	leaf := driftPod("web-1-a", "docker.io/library/nginx:1.21", "sha256:1111")
+	pod := &leaf.data.pod
+	pod.Spec.InitContainers = []v1.Container{{Name: "setup", Image: "busybox:1.36"}}
+	pod.Status.InitContainerStatuses = []v1.ContainerStatus{
+		{Name: "setup", Image: "docker.io/library/busybox:1.36", ImageID: "docker.io/library/busybox@sha256:3333"},
+	}
+
+	images := collectRunningImages(leaf, nil)
+	if len(images) != 2 {
+		t.Fatalf("expected 2 running images got %d", len(images))
+	}
+	if images[0].container != "setup" || images[0].digest != "sha256:3333" {
+		t.Errorf("Output %s %s not equal to expected setup sha256:3333", images[0].container, images[0].digest)
+	}
+}
+
+func TestImageDriftLocalImageID(t *testing.T) {
+	local := driftPod("web-1-b", "docker.io/library/nginx:1.21", "")
+	local.data.pod.Status.ContainerStatuses[0].ImageID = "sha256:9999"
+	pullable := driftPod("web-1-c", "docker.io/library/nginx:1.21", "")
+	pullable.data.pod.Status.ContainerStatuses[0].ImageID = "docker-pullable://nginx@sha256:1111"
+
+	replica := &LeafNode{name: "web-1", kind: TypeNameReplicaSet, data: ParentData{kind: TypeNameReplicaSet}, child: []*LeafNode{
+		driftPod("web-1-a", "docker.io/library/nginx:1.21", "sha256:1111"),
+		local,
+		pullable,
+	}}
+
+	groups := groupByWorkload(collectRunningImages(replica, nil))
+	if len(groups) != 1 {
+		t.Fatalf("expected 1 group got %d", len(groups))
+	}
+
+	// the local image id has no digest to compare so it is not counted as drift
+	cells := groups[0].workloadCells()
+	if cells[5].number != 1 || cells[7].text != "sha256:1111" || len(cells[8].text) > 0 {
+		t.Errorf("Output %d %s %s not equal to expected 1 sha256:1111 and no drift", cells[5].number, cells[7].text, cells[8].text)
+	}
+	if rows := groups[0].repositoryRows(); len(rows) != 0 {
+		t.Errorf("Output %d rows not equal to expected 0", len(rows))
+	}
+}
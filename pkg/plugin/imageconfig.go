@@ -0,0 +1,312 @@
+package plugin
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// largest file read from a tarball, configs and manifests are small so we skip over the image layers
+const maxImageConfigSize = 4 * 1024 * 1024
+
+// imageConfig holds the runtime defaults from an images config
+type imageConfig struct {
+	User         string              `json:"User"`
+	ExposedPorts map[string]struct{} `json:"ExposedPorts"`
+	Env          []string            `json:"Env"`
+	Entrypoint   []string            `json:"Entrypoint"`
+	Cmd          []string            `json:"Cmd"`
+	WorkingDir   string              `json:"WorkingDir"`
+}
+
+// Ports returns the exposed ports in sort order
+func (c imageConfig) Ports() []string {
+	ports := []string{}
+	for port := range c.ExposedPorts {
+		ports = append(ports, port)
+	}
+	sort.Strings(ports)
+	return ports
+}
+
+// imageConfigFile is the layout of the image config json, only the config section is used
+type imageConfigFile struct {
+	Config imageConfig `json:"config"`
+}
+
+// ociDescriptor is an entry in an OCI index.json or image index
+type ociDescriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Annotations map[string]string `json:"annotations"`
+	Platform    *struct {
+		Architecture string `json:"architecture"`
+		OS           string `json:"os"`
+	} `json:"platform"`
+}
+
+// ociIndex is the layout of an OCI index.json or image index blob
+type ociIndex struct {
+	Manifests []ociDescriptor `json:"manifests"`
+}
+
+// ociManifest is the layout of an OCI or docker v2 image manifest blob
+type ociManifest struct {
+	Config ociDescriptor `json:"config"`
+}
+
+// dockerManifest is an entry in the manifest.json written by docker save
+type dockerManifest struct {
+	Config   string   `json:"Config"`
+	RepoTags []string `json:"RepoTags"`
+}
+
+// imageConfigStore holds image configs keyed by the normalised image name and by digest
+type imageConfigStore struct {
+	configs map[string]imageConfig
+}
+
+// loadImageConfigDir reads every OCI image layout and docker save output found in dir, the directory itself,
+//
+//	its sub directories and any .tar files in it are checked
+func loadImageConfigDir(dir string) (*imageConfigStore, error) {
+	store := imageConfigStore{configs: make(map[string]imageConfig)}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read image config directory: %w", err)
+	}
+
+	readDir := func(base string) func(string) ([]byte, error) {
+		return func(name string) ([]byte, error) {
+			return os.ReadFile(filepath.Join(base, filepath.FromSlash(name)))
+		}
+	}
+
+	if err := store.load(readDir(dir)); err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		fullPath := filepath.Join(dir, entry.Name())
+		switch {
+		case entry.IsDir():
+			err = store.load(readDir(fullPath))
+		case strings.HasSuffix(entry.Name(), ".tar"):
+			var files map[string][]byte
+			files, err = readTarFiles(fullPath)
+			if err == nil {
+				err = store.load(func(name string) ([]byte, error) {
+					if data, ok := files[path.Clean(name)]; ok {
+						return data, nil
+					}
+					return nil, os.ErrNotExist
+				})
+			}
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to load image config from %s: %w", fullPath, err)
+		}
+	}
+
+	if len(store.configs) == 0 {
+		return nil, errors.New("error: no OCI image layouts or docker save images found in " + dir)
+	}
+
+	return &store, nil
+}
+
+// readTarFiles returns the contents of all the small files in a tarball
+func readTarFiles(filename string) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+
+	file, err := os.Open(filename)
+	if err != nil {
+		return files, err
+	}
+	defer file.Close()
+
+	reader := tar.NewReader(file)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return files, err
+		}
+
+		if header.Typeflag != tar.TypeReg || header.Size > maxImageConfigSize {
+			continue
+		}
+
+		data, err := io.ReadAll(reader)
+		if err != nil {
+			return files, err
+		}
+		files[path.Clean(header.Name)] = data
+	}
+
+	return files, nil
+}
+
+// load reads an OCI layout or docker save output using readFile, nothing is loaded when neither are found
+func (s *imageConfigStore) load(readFile func(string) ([]byte, error)) error {
+	if data, err := readFile("index.json"); err == nil {
+		index := ociIndex{}
+		if err := json.Unmarshal(data, &index); err != nil {
+			return err
+		}
+		for _, desc := range index.Manifests {
+			if err := s.loadOCIDescriptor(readFile, desc, desc.Annotations); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if data, err := readFile("manifest.json"); err == nil {
+		manifests := []dockerManifest{}
+		if err := json.Unmarshal(data, &manifests); err != nil {
+			return err
+		}
+		for _, manifest := range manifests {
+			config, err := readImageConfig(readFile, manifest.Config)
+			if err != nil {
+				return err
+			}
+			for _, tag := range manifest.RepoTags {
+				s.add(tag, config)
+			}
+			// docker names the config file after its digest
+			s.configs["sha256:"+strings.TrimSuffix(path.Base(manifest.Config), ".json")] = config
+		}
+	}
+
+	return nil
+}
+
+// loadOCIDescriptor follows an index entry to the image config, multi platform images use the linux/amd64
+//
+//	image or the first image listed when there isnt one, platforms whose blobs are missing are skipped
+func (s *imageConfigStore) loadOCIDescriptor(readFile func(string) ([]byte, error), desc ociDescriptor, annotations map[string]string) error {
+	data, err := readFile(blobPath(desc.Digest))
+	if err != nil {
+		return err
+	}
+
+	if strings.Contains(desc.MediaType, "index") || strings.Contains(desc.MediaType, "manifest.list") {
+		index := ociIndex{}
+		if err := json.Unmarshal(data, &index); err != nil {
+			return err
+		}
+		if len(index.Manifests) == 0 {
+			return nil
+		}
+
+		// try linux/amd64 first then the rest in the order they are listed
+		candidates := append([]ociDescriptor{}, index.Manifests...)
+		for i, m := range candidates {
+			if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				candidates = append([]ociDescriptor{m}, append(candidates[:i:i], candidates[i+1:]...)...)
+				break
+			}
+		}
+
+		for _, chosen := range candidates {
+			err := s.loadOCIDescriptor(readFile, chosen, annotations)
+			if errors.Is(err, os.ErrNotExist) {
+				// partial copies often only include some of the platforms
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			// the pod is pulled by the index digest so it needs to point at the same config
+			s.configs[desc.Digest] = s.configs[chosen.Digest]
+			return nil
+		}
+
+		return fmt.Errorf("no platform of %s has all of its blobs: %w", desc.Digest, os.ErrNotExist)
+	}
+
+	manifest := ociManifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return err
+	}
+
+	config, err := readImageConfig(readFile, blobPath(manifest.Config.Digest))
+	if err != nil {
+		return err
+	}
+
+	s.configs[desc.Digest] = config
+	for _, key := range []string{"io.containerd.image.name", "org.opencontainers.image.ref.name"} {
+		if name, ok := annotations[key]; ok {
+			s.add(name, config)
+		}
+	}
+
+	return nil
+}
+
+// readImageConfig reads and decodes an image config file
+func readImageConfig(readFile func(string) ([]byte, error), name string) (imageConfig, error) {
+	file := imageConfigFile{}
+
+	data, err := readFile(name)
+	if err != nil {
+		return file.Config, err
+	}
+
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file.Config, err
+	}
+
+	return file.Config, nil
+}
+
+// blobPath converts a digest into its path in an OCI layout
+func blobPath(digest string) string {
+	return "blobs/" + strings.Replace(digest, ":", "/", 1)
+}
+
+// add stores a config under its normalised image name
+func (s *imageConfigStore) add(imageName string, config imageConfig) {
+	ref := parseImageReference(imageName)
+	ref.Digest = ""
+	s.configs[imageReferenceString(ref)] = config
+}
+
+// Get returns the config of an image, pinned images are only looked up by digest as the tag could point
+//
+//	at a different image
+func (s *imageConfigStore) Get(imageName string) (imageConfig, bool) {
+	if s == nil {
+		return imageConfig{}, false
+	}
+
+	ref := parseImageReference(imageName)
+	if len(ref.Digest) > 0 {
+		config, ok := s.configs[ref.Digest]
+		return config, ok
+	}
+
+	config, ok := s.configs[imageReferenceString(ref)]
+	return config, ok
+}
+
+// imageUserName returns the USER from the image config, images without a USER run as root
+func imageUserName(config imageConfig) string {
+	if len(config.User) == 0 {
+		return "root"
+	}
+	return config.User
+}
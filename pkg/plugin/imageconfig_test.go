@@ -0,0 +1,164 @@
+package plugin
+
+import (
+	"archive/tar"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeTestFiles writes each named file below dir creating any sub directories
+func writeTestFiles(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		fullPath := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// writeTestTar writes the named files into a single tarball
+func writeTestTar(t *testing.T, filename string, files map[string]string) {
+	file, err := os.Create(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	writer := tar.NewWriter(file)
+	for name, data := range files {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), Typeflag: tar.TypeReg}
+		if err := writer.WriteHeader(header); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := writer.Write([]byte(data)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Close(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// ociLayoutFiles is an OCI image layout holding a multi platform index for app:1.0
+var ociLayoutFiles = map[string]string{
+	"index.json": `{"manifests": [{
+		"mediaType": "application/vnd.oci.image.index.v1+json",
+		"digest": "sha256:1000",
+		"annotations": {"io.containerd.image.name": "registry.io/team/app:1.0"}
+	}]}`,
+	"blobs/sha256/1000": `{"manifests": [
+		{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:2000", "platform": {"architecture": "arm64", "os": "linux"}},
+		{"mediaType": "application/vnd.oci.image.manifest.v1+json", "digest": "sha256:3000", "platform": {"architecture": "amd64", "os": "linux"}}
+	]}`,
+	"blobs/sha256/2000": `{"config": {"digest": "sha256:2001"}}`,
+	"blobs/sha256/2001": `{"config": {"User": "arm"}}`,
+	"blobs/sha256/3000": `{"config": {"digest": "sha256:3001"}}`,
+	"blobs/sha256/3001": `{"config": {"User": "1000", "ExposedPorts": {"8080/tcp": {}}}}`,
+}
+
+// dockerSaveFiles is the output of docker save for nginx:1.21
+var dockerSaveFiles = map[string]string{
+	"manifest.json": `[{"Config": "4000.json", "RepoTags": ["nginx:1.21"]}]`,
+	"4000.json":     `{"config": {"User": "nginx"}}`,
+}
+
+// dockerTarFiles is the output of docker save for redis:7 written as a tarball
+var dockerTarFiles = map[string]string{
+	"manifest.json":     `[{"Config": "blobs/sha256/5000", "RepoTags": ["redis:7"]}]`,
+	"blobs/sha256/5000": `{"config": {"User": "redis"}}`,
+}
+
+// ******************
+// loadImageConfigDir
+// ******************
+type loadImageConfigDirTest struct {
+	image    string
+	expected string // user from the image config
+	found    bool
+}
+
+var loadImageConfigDirTests = []loadImageConfigDirTest{
+	// OCI layout in the top directory, linux/amd64 is picked from the index
+	{"registry.io/team/app:1.0", "1000", true},
+	{"registry.io/team/app@sha256:1000", "1000", true},
+	{"registry.io/team/app@sha256:3000", "1000", true},
+	{"registry.io/team/app:1.0@sha256:9999", "", false},
+	{"registry.io/team/app:2.0", "", false},
+	// docker save output in a sub directory
+	{"nginx:1.21", "nginx", true},
+	{"docker.io/library/nginx:1.21", "nginx", true},
+	{"nginx", "", false},
+	// docker save output in a tarball
+	{"redis:7", "redis", true},
+	{"redis@sha256:5000", "redis", true},
+	{"redis@sha256:9999", "", false},
+}
+
+func TestLoadImageConfigDir(t *testing.T) {
+	dir := t.TempDir()
+	writeTestFiles(t, dir, ociLayoutFiles)
+	writeTestFiles(t, filepath.Join(dir, "nginx"), dockerSaveFiles)
+	writeTestTar(t, filepath.Join(dir, "redis.tar"), dockerTarFiles)
+
+	store, err := loadImageConfigDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range loadImageConfigDirTests {
+		config, ok := store.Get(test.image)
+		if ok != test.found {
+			t.Errorf("Output found %t not equal to expected %t for %s", ok, test.found, test.image)
+			continue
+		}
+		if config.User != test.expected {
+			t.Errorf("Output %s not equal to expected %s for %s", config.User, test.expected, test.image)
+		}
+	}
+
+	config, _ := store.Get("registry.io/team/app:1.0")
+	if ports := config.Ports(); len(ports) != 1 || ports[0] != "8080/tcp" {
+		t.Errorf("Output %v not equal to expected [8080/tcp]", ports)
+	}
+
+}
+
+func TestLoadImageConfigDirPartial(t *testing.T) {
+	files := map[string]string{}
+	for name, data := range ociLayoutFiles {
+		files[name] = data
+	}
+	// the linux/amd64 manifest was not copied so the arm64 image is used
+	delete(files, "blobs/sha256/3000")
+
+	dir := t.TempDir()
+	writeTestFiles(t, dir, files)
+
+	store, err := loadImageConfigDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config, ok := store.Get("registry.io/team/app:1.0"); !ok || config.User != "arm" {
+		t.Errorf("Output %s %t not equal to expected arm true", config.User, ok)
+	}
+
+	// no platform is usable
+	delete(files, "blobs/sha256/2001")
+	dir = t.TempDir()
+	writeTestFiles(t, dir, files)
+
+	if _, err := loadImageConfigDir(dir); err == nil {
+		t.Errorf("expected an error when no platform has all of its blobs")
+	}
+
+}
+
+func TestLoadImageConfigDirEmpty(t *testing.T) {
+	if _, err := loadImageConfigDir(t.TempDir()); err == nil {
+		t.Errorf("expected an error when no images are found")
+	}
+}
@@ -10,12 +10,15 @@ import (
 	a1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
 	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
 	metricsclientset "k8s.io/metrics/pkg/client/clientset/versioned"
+	"sigs.k8s.io/yaml"
 )
 
 const TypeIDContainer string = "C"
@@ -40,6 +43,8 @@ const TypeIDJob string = "J"
 const TypeNameJob string = "Job"
 const TypeIDCronJob string = "O"
 const TypeNameCronJob string = "CronJob"
+const TypeIDNamespace string = "M"
+const TypeNameNamespace string = "Namespace"
 
 // const TypeID string= ""
 // const TypeName string = ""
@@ -52,13 +57,24 @@ type Connector struct {
 	metricFlags    *genericclioptions.ConfigFlags
 	configMapArray map[string]map[string]string
 	setNameSpace   string
-	podList        []v1.Pod                     // List of Pods
-	replicaList    map[string][]a1.ReplicaSet   // list of ReplicaSets
-	daemonList     map[string][]a1.DaemonSet    // list of DaemonSets
-	statefulList   map[string][]a1.StatefulSet  // list of StatefulSet
-	deploymentList map[string][]a1.Deployment   // list of Deployments
-	jobList        map[string][]batchv1.Job     // list of k8s Jobs
-	cronJobList    map[string][]batchv1.CronJob // list of k8s CronJobs
+	podList        []v1.Pod                        // List of Pods
+	replicaList    map[string][]a1.ReplicaSet      // list of ReplicaSets
+	daemonList     map[string][]a1.DaemonSet       // list of DaemonSets
+	statefulList   map[string][]a1.StatefulSet     // list of StatefulSet
+	deploymentList map[string][]a1.Deployment      // list of Deployments
+	jobList        map[string][]batchv1.Job        // list of k8s Jobs
+	cronJobList    map[string][]batchv1.CronJob    // list of k8s CronJobs
+	nodeList       map[string]v1.Node              // cache of nodes already requested by name
+	quotaList      map[string][]v1.ResourceQuota   // list of ResourceQuotas by namespace
+	rbacSnapshot   bool                            // only use rbac objects loaded from a file, the api is never called
+	rbacClusterSet bool                            // cluster roles and bindings have been loaded
+	accountList    map[string][]v1.ServiceAccount  // list of ServiceAccounts by namespace
+	roleList       map[string][]rbacv1.Role        // list of Roles by namespace
+	bindingList    map[string][]rbacv1.RoleBinding // list of RoleBindings by namespace
+	clusterRoles   []rbacv1.ClusterRole            // list of ClusterRoles
+	clusterBinding []rbacv1.ClusterRoleBinding     // list of ClusterRoleBindings
+	eventList      map[string][]v1.Event           // list of Events by the uid of the pod they belong to
+	objectExists   map[string]bool                 // ConfigMaps and Secrets already checked, keyed by kind/namespace/name
 }
 
 type ParentData struct {
@@ -254,6 +270,312 @@ func (c *Connector) GetNodes(nodeNameList []string) ([]v1.Node, error) {
 	return nodes.Items, nil
 }
 
+// GetNode returns a single node by name, nodes are cached so each node is only requested once
+func (c *Connector) GetNode(nodeName string) (v1.Node, error) {
+	if c.nodeList == nil {
+		c.nodeList = make(map[string]v1.Node)
+	}
+
+	if node, ok := c.nodeList[nodeName]; ok {
+		return node, nil
+	}
+
+	node, err := c.clientSet.CoreV1().Nodes().Get(context.TODO(), nodeName, metav1.GetOptions{})
+	if err != nil {
+		return v1.Node{}, fmt.Errorf("failed to retrieve node from server: %w", err)
+	}
+
+	c.nodeList[nodeName] = *node
+	return *node, nil
+}
+
+// GetResourceQuotas returns all ResourceQuotas in the named namespace, each namespace is only requested once
+func (c *Connector) GetResourceQuotas(namespace string) ([]v1.ResourceQuota, error) {
+	if c.quotaList == nil {
+		c.quotaList = make(map[string][]v1.ResourceQuota)
+	}
+
+	if quotas, ok := c.quotaList[namespace]; ok {
+		return quotas, nil
+	}
+
+	quotas, err := c.clientSet.CoreV1().ResourceQuotas(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return []v1.ResourceQuota{}, fmt.Errorf("failed to retrieve ResourceQuota list from server: %w", err)
+	}
+
+	c.quotaList[namespace] = quotas.Items
+	return quotas.Items, nil
+}
+
+// GetOwnerPodTemplate returns the pod template of the object that created the pod, nil is returned
+//
+//	when the pod has no owner or the owner could not be found
+func (c *Connector) GetOwnerPodTemplate(pod v1.Pod) *v1.PodTemplateSpec {
+	for _, v := range pod.GetOwnerReferences() {
+		switch v.Kind {
+		case TypeNameReplicaSet:
+			if replica := c.GetReplicaSet(v.Name, pod.Namespace); replica != nil {
+				return &replica.Spec.Template
+			}
+		case TypeNameStatefulSet:
+			if stateful := c.GetStatefulSet(v.Name, pod.Namespace); stateful != nil {
+				return &stateful.Spec.Template
+			}
+		case TypeNameDaemonSet:
+			if daemon := c.GetDaemonSet(v.Name, pod.Namespace); daemon != nil {
+				return &daemon.Spec.Template
+			}
+		case TypeNameJob:
+			if job := c.GetJob(v.Name, pod.Namespace); job != nil {
+				return &job.Spec.Template
+			}
+		}
+	}
+
+	return nil
+}
+
+// GetPodEvents returns the Events of a pod, each pod is only requested once
+func (c *Connector) GetPodEvents(pod v1.Pod) ([]v1.Event, error) {
+	if c.eventList == nil {
+		c.eventList = make(map[string][]v1.Event)
+	}
+
+	uid := string(pod.UID)
+	if events, ok := c.eventList[uid]; ok {
+		return events, nil
+	}
+
+	selector := metav1.ListOptions{FieldSelector: "involvedObject.uid=" + uid}
+	events, err := c.clientSet.CoreV1().Events(pod.Namespace).List(context.TODO(), selector)
+	if err != nil {
+		return []v1.Event{}, fmt.Errorf("failed to retrieve Event list from server: %w", err)
+	}
+
+	c.eventList[uid] = events.Items
+	return events.Items, nil
+}
+
+// ObjectExists checks if the named ConfigMap or Secret exists, each object is only requested once. When we
+//
+//	dont have permission to read the object it is treated as existing as we cant tell either way
+func (c *Connector) ObjectExists(kind string, name string, namespace string) (bool, error) {
+	var err error
+
+	if c.objectExists == nil {
+		c.objectExists = make(map[string]bool)
+	}
+
+	id := kind + "/" + namespace + "/" + name
+	if exists, ok := c.objectExists[id]; ok {
+		return exists, nil
+	}
+
+	switch kind {
+	case "ConfigMap":
+		_, err = c.clientSet.CoreV1().ConfigMaps(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	case "Secret":
+		_, err = c.clientSet.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
+	default:
+		return false, fmt.Errorf("unable to check objects of kind %s", kind)
+	}
+
+	exists := true
+	if err != nil {
+		switch {
+		case apierrors.IsNotFound(err):
+			exists = false
+		case apierrors.IsForbidden(err):
+			exists = true
+		default:
+			return false, fmt.Errorf("failed to retrieve %s %s from server: %w", kind, name, err)
+		}
+	}
+
+	c.objectExists[id] = exists
+	return exists, nil
+}
+
+// loadNamespaceRBAC loads the ServiceAccounts, Roles and RoleBindings of a namespace, each namespace is only
+//
+//	requested once and nothing is requested when reading from a file
+func (c *Connector) loadNamespaceRBAC(namespace string) error {
+	if c.accountList == nil {
+		c.accountList = make(map[string][]v1.ServiceAccount)
+		c.roleList = make(map[string][]rbacv1.Role)
+		c.bindingList = make(map[string][]rbacv1.RoleBinding)
+	}
+
+	if _, ok := c.bindingList[namespace]; ok || c.rbacSnapshot {
+		return nil
+	}
+
+	accounts, err := c.clientSet.CoreV1().ServiceAccounts(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve ServiceAccount list from server: %w", err)
+	}
+
+	roles, err := c.clientSet.RbacV1().Roles(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve Role list from server: %w", err)
+	}
+
+	bindings, err := c.clientSet.RbacV1().RoleBindings(namespace).List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve RoleBinding list from server: %w", err)
+	}
+
+	c.accountList[namespace] = accounts.Items
+	c.roleList[namespace] = roles.Items
+	c.bindingList[namespace] = bindings.Items
+	return nil
+}
+
+// loadClusterRBAC loads all ClusterRoles and ClusterRoleBindings, they are only requested once and nothing is
+//
+//	requested when reading from a file
+func (c *Connector) loadClusterRBAC() error {
+	if c.rbacClusterSet || c.rbacSnapshot {
+		return nil
+	}
+
+	roles, err := c.clientSet.RbacV1().ClusterRoles().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve ClusterRole list from server: %w", err)
+	}
+
+	bindings, err := c.clientSet.RbacV1().ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to retrieve ClusterRoleBinding list from server: %w", err)
+	}
+
+	c.clusterRoles = roles.Items
+	c.clusterBinding = bindings.Items
+	c.rbacClusterSet = true
+	return nil
+}
+
+// GetServiceAccount returns the named ServiceAccount, nil is returned when it doesnt exist
+func (c *Connector) GetServiceAccount(name string, namespace string) (*v1.ServiceAccount, error) {
+	if err := c.loadNamespaceRBAC(namespace); err != nil {
+		return nil, err
+	}
+
+	for _, sa := range c.accountList[namespace] {
+		if sa.Name == name {
+			return &sa, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetRoleBindings returns all RoleBindings in the namespace
+func (c *Connector) GetRoleBindings(namespace string) ([]rbacv1.RoleBinding, error) {
+	if err := c.loadNamespaceRBAC(namespace); err != nil {
+		return []rbacv1.RoleBinding{}, err
+	}
+	return c.bindingList[namespace], nil
+}
+
+// GetRole returns the named Role, nil is returned when it doesnt exist
+func (c *Connector) GetRole(name string, namespace string) (*rbacv1.Role, error) {
+	if err := c.loadNamespaceRBAC(namespace); err != nil {
+		return nil, err
+	}
+
+	for _, role := range c.roleList[namespace] {
+		if role.Name == name {
+			return &role, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetClusterRoleBindings returns all ClusterRoleBindings
+func (c *Connector) GetClusterRoleBindings() ([]rbacv1.ClusterRoleBinding, error) {
+	if err := c.loadClusterRBAC(); err != nil {
+		return []rbacv1.ClusterRoleBinding{}, err
+	}
+	return c.clusterBinding, nil
+}
+
+// GetClusterRole returns the named ClusterRole, nil is returned when it doesnt exist
+func (c *Connector) GetClusterRole(name string) (*rbacv1.ClusterRole, error) {
+	if err := c.loadClusterRBAC(); err != nil {
+		return nil, err
+	}
+
+	for _, role := range c.clusterRoles {
+		if role.Name == name {
+			return &role, nil
+		}
+	}
+	return nil, nil
+}
+
+// addSnapshotRBAC stores a ServiceAccount, Role, ClusterRole or binding read from a yaml file so it can be used
+//
+//	in place of the api, objects without a namespace are placed in the default namespace
+func (c *Connector) addSnapshotRBAC(kind string, input []byte) error {
+	var meta metav1.PartialObjectMetadata
+
+	if err := yaml.Unmarshal(input, &meta); err != nil {
+		return err
+	}
+
+	namespace := meta.Namespace
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+
+	if c.accountList == nil {
+		c.accountList = make(map[string][]v1.ServiceAccount)
+		c.roleList = make(map[string][]rbacv1.Role)
+		c.bindingList = make(map[string][]rbacv1.RoleBinding)
+	}
+
+	switch kind {
+	case "ServiceAccount":
+		var sa v1.ServiceAccount
+		if err := yaml.Unmarshal(input, &sa); err != nil {
+			return err
+		}
+		c.accountList[namespace] = append(c.accountList[namespace], sa)
+
+	case "Role":
+		var role rbacv1.Role
+		if err := yaml.Unmarshal(input, &role); err != nil {
+			return err
+		}
+		c.roleList[namespace] = append(c.roleList[namespace], role)
+
+	case "RoleBinding":
+		var binding rbacv1.RoleBinding
+		if err := yaml.Unmarshal(input, &binding); err != nil {
+			return err
+		}
+		binding.Namespace = namespace
+		c.bindingList[namespace] = append(c.bindingList[namespace], binding)
+
+	case "ClusterRole":
+		var role rbacv1.ClusterRole
+		if err := yaml.Unmarshal(input, &role); err != nil {
+			return err
+		}
+		c.clusterRoles = append(c.clusterRoles, role)
+
+	case "ClusterRoleBinding":
+		var binding rbacv1.ClusterRoleBinding
+		if err := yaml.Unmarshal(input, &binding); err != nil {
+			return err
+		}
+		c.clusterBinding = append(c.clusterBinding, binding)
+	}
+
+	return nil
+}
+
 // SelectMatchingPodSpec select pods to inclue or exclude based on the field in v1.Pods.Spec an operator (!=, ==, =) and a string value to match with
 func (c *Connector) SelectMatchinghPodSpec(pods []v1.Pod) ([]v1.Pod, error) {
 	var newPodList []v1.Pod
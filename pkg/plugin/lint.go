@@ -0,0 +1,606 @@
+package plugin
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var lintShort = "Check containers against a list of best practice rules"
+
+var lintDescription = ` Runs each container through a list of best practice rules and prints every rule that failed along with
+its severity. Rules can be turned on or off by id with --enable and --disable, and can be silenced for a
+single pod by listing the rule ids in the pod annotation kubectl-ice/lint-disable.
+
+Use --fail-on to exit with an error when a finding is at or above the chosen severity, combined with -f
+and -o sarif or -o junit this allows manifests to be checked as part of a CI pipeline.
+
+Rules:
+  ICE001 error    missing cpu or memory limit
+  ICE002 error    limit is lower than the request
+  ICE003 warning  image uses the latest tag or has no tag
+  ICE004 note     imagePullPolicy Always used on an image pinned by digest
+  ICE005 warning  no readiness probe
+  ICE006 warning  liveness probe is the same as the readiness probe
+  ICE007 error    probe timeout is greater than or equal to its period
+  ICE008 warning  root filesystem is writable
+  ICE009 note     init container has no resource requests
+
+Ephemeral containers cant set resources or probes so only the image rules ICE003 and ICE004 are run on them.`
+
+var lintExample = `  # Check all containers in the current namespace
+  %[1]s lint
+
+  # Check a manifest file and output the results for CI
+  %[1]s lint -f deployment.yaml -o sarif
+
+  # Only run the image tag and probe rules
+  %[1]s lint --enable ICE003,ICE005,ICE006,ICE007
+
+  # Run every rule apart from the writable root filesystem check
+  %[1]s lint --disable ICE008
+
+  # Exit with an error if any rule with a severity of error fails
+  %[1]s lint -f deployment.yaml --fail-on error
+
+  # Check manifests from stdin and output the results as JUnit XML
+  helm template ./chart | %[1]s lint -o junit`
+
+// lintDisableAnnotation lists the rule ids to skip for a single pod
+const lintDisableAnnotation = "kubectl-ice/lint-disable"
+
+const (
+	severityError   = "error"
+	severityWarning = "warning"
+	severityNote    = "note"
+)
+
+// severityLevels the severities in order from least to most important
+var severityLevels = []string{severityNote, severityWarning, severityError}
+
+// lintRule is a single best practice check, check returns a message for each problem found
+type lintRule struct {
+	id          string
+	name        string
+	severity    string
+	description string
+	ephemeral   bool // the rule is also run on ephemeral containers
+	check       func(pod v1.Pod, container v1.Container, info BuilderInformation) []string
+}
+
+var lintRules = []lintRule{
+	{
+		id:          "ICE001",
+		name:        "missing-limits",
+		severity:    severityError,
+		description: "missing cpu or memory limit",
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			var out []string
+			for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+				if _, ok := container.Resources.Limits[name]; !ok {
+					out = append(out, fmt.Sprintf("no %s limit set", name))
+				}
+			}
+			return out
+		},
+	},
+	{
+		id:          "ICE002",
+		name:        "limit-below-request",
+		severity:    severityError,
+		description: "limit is lower than the request",
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			var out []string
+			for name, limit := range container.Resources.Limits {
+				if request, ok := container.Resources.Requests[name]; ok && limit.Cmp(request) < 0 {
+					out = append(out, fmt.Sprintf("%s limit %s is lower than request %s", name, limit.String(), request.String()))
+				}
+			}
+			sort.Strings(out)
+			return out
+		},
+	},
+	{
+		id:          "ICE003",
+		name:        "latest-tag",
+		severity:    severityWarning,
+		description: "image uses the latest tag or has no tag",
+		ephemeral:   true,
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			if strings.Contains(container.Image, "@") {
+				return nil
+			}
+			tag := lintImageTag(container.Image)
+			if len(tag) == 0 {
+				return []string{"image " + container.Image + " has no tag"}
+			}
+			if tag == "latest" {
+				return []string{"image " + container.Image + " uses the latest tag"}
+			}
+			return nil
+		},
+	},
+	{
+		id:          "ICE004",
+		name:        "always-pull-digest",
+		severity:    severityNote,
+		description: "imagePullPolicy Always used on an image pinned by digest",
+		ephemeral:   true,
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			if container.ImagePullPolicy == v1.PullAlways && strings.Contains(container.Image, "@") {
+				return []string{"image is pinned by digest so pulling it every time has no effect"}
+			}
+			return nil
+		},
+	},
+	{
+		id:          "ICE005",
+		name:        "no-readiness-probe",
+		severity:    severityWarning,
+		description: "no readiness probe",
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			if info.ContainerType != TypeIDContainer {
+				return nil
+			}
+			if container.ReadinessProbe == nil {
+				return []string{"no readiness probe set"}
+			}
+			return nil
+		},
+	},
+	{
+		id:          "ICE006",
+		name:        "liveness-equals-readiness",
+		severity:    severityWarning,
+		description: "liveness probe is the same as the readiness probe",
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			if container.LivenessProbe == nil || container.ReadinessProbe == nil {
+				return nil
+			}
+			if reflect.DeepEqual(container.LivenessProbe.ProbeHandler, container.ReadinessProbe.ProbeHandler) {
+				return []string{"liveness and readiness probes use the same check, a failing dependency will restart the container"}
+			}
+			return nil
+		},
+	},
+	{
+		id:          "ICE007",
+		name:        "probe-timeout-period",
+		severity:    severityError,
+		description: "probe timeout is greater than or equal to its period",
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			var out []string
+			probes := []struct {
+				name  string
+				probe *v1.Probe
+			}{
+				{"startup", container.StartupProbe},
+				{"liveness", container.LivenessProbe},
+				{"readiness", container.ReadinessProbe},
+			}
+
+			for _, p := range probes {
+				if p.probe == nil {
+					continue
+				}
+				// use the kubernetes defaults when the values are not set
+				timeout := p.probe.TimeoutSeconds
+				if timeout == 0 {
+					timeout = 1
+				}
+				period := p.probe.PeriodSeconds
+				if period == 0 {
+					period = 10
+				}
+				if timeout >= period {
+					out = append(out, fmt.Sprintf("%s probe timeout %ds is not less than its period %ds", p.name, timeout, period))
+				}
+			}
+			return out
+		},
+	},
+	{
+		id:          "ICE008",
+		name:        "writable-root-fs",
+		severity:    severityWarning,
+		description: "root filesystem is writable",
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			csc := container.SecurityContext
+			if csc == nil || csc.ReadOnlyRootFilesystem == nil || !*csc.ReadOnlyRootFilesystem {
+				return []string{"readOnlyRootFilesystem is not set to true"}
+			}
+			return nil
+		},
+	},
+	{
+		id:          "ICE009",
+		name:        "init-no-requests",
+		severity:    severityNote,
+		description: "init container has no resource requests",
+		check: func(pod v1.Pod, container v1.Container, info BuilderInformation) []string {
+			if info.ContainerType != TypeIDInitContainer {
+				return nil
+			}
+			if len(container.Resources.Requests) == 0 {
+				return []string{"init container has no resource requests"}
+			}
+			return nil
+		},
+	},
+}
+
+// lintImageTag returns the tag part of an image name, an empty string is returned when no tag is set
+func lintImageTag(image string) string {
+	name := image
+	if i := strings.LastIndex(name, "/"); i >= 0 {
+		// skip over the registry as it can contain a port number
+		name = name[i+1:]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
+
+// severityIndex returns the position of the severity in severityLevels, -1 is returned for unknown values
+func severityIndex(severity string) int {
+	for i, name := range severityLevels {
+		if name == severity {
+			return i
+		}
+	}
+	return -1
+}
+
+func Lint(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+	log := logger{location: "Lint"}
+	log.Debug("Start")
+
+	loopinfo := lint{}
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	if err := loopinfo.setRules(cmd); err != nil {
+		return err
+	}
+
+	failOn := ""
+	if cmd.Flag("fail-on") != nil {
+		failOn = cmd.Flag("fail-on").Value.String()
+		if len(failOn) > 0 && severityIndex(failOn) < 0 {
+			return errors.New("error: fail-on must be one of note, warning or error")
+		}
+	}
+
+	table := Table{}
+	builder.Table = &table
+
+	builder.ShowTreeView = commonFlagList.showTreeView
+	loopinfo.Builder = &builder
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	switch commonFlagList.outputAs {
+	case "sarif":
+		if err := loopinfo.printSarif(cmd.Root().Version, commonFlagList.inputFilename); err != nil {
+			return err
+		}
+	case "junit":
+		if err := printJUnit("lint", loopinfo.Checked); err != nil {
+			return err
+		}
+	default:
+		outputTableAs(table, commonFlagList.outputAs)
+	}
+
+	if len(failOn) > 0 {
+		count := 0
+		for _, container := range loopinfo.Checked {
+			for _, result := range container.results {
+				if severityIndex(result.level) >= severityIndex(failOn) {
+					count++
+				}
+			}
+		}
+		if count > 0 {
+			return fmt.Errorf("lint found %d issue(s) with a severity of %s or above", count, failOn)
+		}
+	}
+
+	return nil
+
+}
+
+type lint struct {
+	Builder *RowBuilder // used to leave out findings hidden by --match, can be nil
+	Rules   []lintRule
+	Checked []checkedContainer
+}
+
+// setRules selects the rules to run using the enable and disable flags
+func (s *lint) setRules(cmd *cobra.Command) error {
+	enabled := map[string]bool{}
+	disabled := map[string]bool{}
+	known := map[string]bool{}
+
+	for _, rule := range lintRules {
+		known[rule.id] = true
+	}
+
+	for flag, list := range map[string]map[string]bool{"enable": enabled, "disable": disabled} {
+		if cmd.Flag(flag) == nil {
+			continue
+		}
+		for _, id := range strings.Split(cmd.Flag(flag).Value.String(), ",") {
+			id = strings.ToUpper(strings.TrimSpace(id))
+			if len(id) == 0 {
+				continue
+			}
+			if !known[id] {
+				return fmt.Errorf("error: unknown lint rule %s", id)
+			}
+			list[id] = true
+		}
+	}
+
+	for _, rule := range lintRules {
+		if len(enabled) > 0 && !enabled[rule.id] {
+			continue
+		}
+		if disabled[rule.id] {
+			continue
+		}
+		s.Rules = append(s.Rules, rule)
+	}
+
+	return nil
+}
+
+func (s *lint) Headers() []string {
+	return []string{
+		"RULE", "SEVERITY", "MESSAGE",
+	}
+}
+
+func (s *lint) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *lint) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *lint) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	var count int64
+	severity := ""
+
+	// show the most important severity found below the branch
+	for _, r := range rows {
+		if severityIndex(r[1].text) > severityIndex(severity) {
+			severity = r[1].text
+		}
+		// finding rows count as one, branch rows carry the total of their children
+		count += r[2].number
+	}
+
+	message := NewCellText("")
+	if count > 0 {
+		message = NewCellText(fmt.Sprintf("%d finding(s)", count))
+	}
+	message.number = count
+
+	return []Cell{
+		NewCellText(""),
+		NewCellText(severity),
+		message,
+	}, nil
+}
+
+func (s *lint) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return s.lintBuildRows(container, info), nil
+}
+
+func (s *lint) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return s.lintBuildRows(v1.Container(container.EphemeralContainerCommon), info), nil
+}
+
+func (s *lint) lintBuildRows(container v1.Container, info BuilderInformation) [][]Cell {
+	var out [][]Cell
+
+	pod := info.Data.pod
+	checked := checkedContainer{
+		namespace:     pod.Namespace,
+		podName:       pod.Name,
+		containerName: container.Name,
+	}
+
+	// rules can be silenced for a single pod
+	silenced := map[string]bool{}
+	for _, id := range strings.Split(pod.Annotations[lintDisableAnnotation], ",") {
+		silenced[strings.ToUpper(strings.TrimSpace(id))] = true
+	}
+
+	for _, rule := range s.Rules {
+		if silenced[rule.id] {
+			continue
+		}
+		if info.ContainerType == TypeIDEphemeralContainer && !rule.ephemeral {
+			continue
+		}
+
+		for _, message := range rule.check(pod, container, info) {
+			messageCell := NewCellText(message)
+			messageCell.number = 1
+
+			row := []Cell{
+				NewCellText(rule.id),
+				NewCellText(rule.severity),
+				messageCell,
+			}
+			out = append(out, row)
+
+			// findings hidden by --match are not reported or counted by --fail-on
+			if s.Builder != nil && s.Builder.matchShouldExclude(s.Builder.makeFullRow(&info, 0, row)) {
+				continue
+			}
+
+			checked.results = append(checked.results, checkResult{
+				name:    rule.id,
+				level:   rule.severity,
+				message: message,
+			})
+		}
+	}
+	s.Checked = append(s.Checked, checked)
+
+	return out
+}
+
+// sarif* types are the parts of the SARIF 2.1.0 format needed to report lint findings
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name           string      `json:"name"`
+	Version        string      `json:"version,omitempty"`
+	InformationURI string      `json:"informationUri"`
+	Rules          []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID                   string       `json:"id"`
+	Name                 string       `json:"name"`
+	ShortDescription     sarifMessage `json:"shortDescription"`
+	DefaultConfiguration sarifConfig  `json:"defaultConfiguration"`
+}
+
+type sarifConfig struct {
+	Level string `json:"level"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+	Kind               string `json:"kind"`
+}
+
+// printSarif prints the findings as a SARIF log, filename is used as the location of each finding when set
+func (s *lint) printSarif(version string, filename string) error {
+	driver := sarifDriver{
+		Name:           "kubectl-ice",
+		Version:        version,
+		InformationURI: "https://github.com/NimbleArchitect/kubectl-ice",
+		Rules:          []sarifRule{},
+	}
+
+	for _, rule := range s.Rules {
+		driver.Rules = append(driver.Rules, sarifRule{
+			ID:                   rule.id,
+			Name:                 rule.name,
+			ShortDescription:     sarifMessage{Text: rule.description},
+			DefaultConfiguration: sarifConfig{Level: rule.severity},
+		})
+	}
+
+	results := []sarifResult{}
+	for _, container := range s.Checked {
+		location := sarifLocation{
+			LogicalLocations: []sarifLogicalLocation{{
+				FullyQualifiedName: container.namespace + "/" + container.podName + "/" + container.containerName,
+				Kind:               "object",
+			}},
+		}
+		if len(filename) > 0 && filename != "-" {
+			location.PhysicalLocation = &sarifPhysicalLocation{
+				ArtifactLocation: sarifArtifactLocation{URI: filename},
+			}
+		}
+
+		for _, result := range container.results {
+			results = append(results, sarifResult{
+				RuleID:    result.name,
+				Level:     result.level,
+				Message:   sarifMessage{Text: result.message},
+				Locations: []sarifLocation{location},
+			})
+		}
+	}
+
+	out, err := json.MarshalIndent(sarifLog{
+		Version: "2.1.0",
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Runs: []sarifRun{{
+			Tool:    sarifTool{Driver: driver},
+			Results: results,
+		}},
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(string(out))
+	return nil
+}
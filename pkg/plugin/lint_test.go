@@ -0,0 +1,146 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+)
+
+// lintRuleByID returns the named rule from lintRules
+func lintRuleByID(t *testing.T, id string) lintRule {
+	for _, rule := range lintRules {
+		if rule.id == id {
+			return rule
+		}
+	}
+	t.Fatalf("lint rule %s not found", id)
+	return lintRule{}
+}
+
+func lintTestProbe(port string) *v1.Probe {
+	return &v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Path: "/" + port}}}
+}
+
+// *********
+// lintRules
+// *********
+type lintRuleTest struct {
+	id            string
+	container     v1.Container
+	containerType string
+	expected      int // number of findings
+}
+
+var lintReadOnly = true
+
+var lintRuleTests = []lintRuleTest{
+	{"ICE001", v1.Container{}, TypeIDContainer, 2},
+	{"ICE001", testContainer("", "100m"), TypeIDContainer, 0},
+	{"ICE001", v1.Container{Resources: v1.ResourceRequirements{Limits: v1.ResourceList{
+		v1.ResourceCPU: apires.MustParse("1"),
+	}}}, TypeIDContainer, 1},
+	{"ICE002", testContainer("200m", "100m"), TypeIDContainer, 2},
+	{"ICE002", testContainer("100m", "100m"), TypeIDContainer, 0},
+	{"ICE002", testContainer("100m", ""), TypeIDContainer, 0},
+	{"ICE003", v1.Container{Image: "nginx"}, TypeIDContainer, 1},
+	{"ICE003", v1.Container{Image: "nginx:latest"}, TypeIDContainer, 1},
+	{"ICE003", v1.Container{Image: "registry:5000/nginx"}, TypeIDContainer, 1},
+	{"ICE003", v1.Container{Image: "registry:5000/nginx:1.23"}, TypeIDContainer, 0},
+	{"ICE003", v1.Container{Image: "nginx@sha256:0123"}, TypeIDContainer, 0},
+	{"ICE004", v1.Container{Image: "nginx@sha256:0123", ImagePullPolicy: v1.PullAlways}, TypeIDContainer, 1},
+	{"ICE004", v1.Container{Image: "nginx@sha256:0123", ImagePullPolicy: v1.PullIfNotPresent}, TypeIDContainer, 0},
+	{"ICE004", v1.Container{Image: "nginx:1.23", ImagePullPolicy: v1.PullAlways}, TypeIDContainer, 0},
+	{"ICE005", v1.Container{}, TypeIDContainer, 1},
+	{"ICE005", v1.Container{}, TypeIDInitContainer, 0},
+	{"ICE005", v1.Container{ReadinessProbe: lintTestProbe("ready")}, TypeIDContainer, 0},
+	{"ICE006", v1.Container{LivenessProbe: lintTestProbe("health"), ReadinessProbe: lintTestProbe("health")}, TypeIDContainer, 1},
+	{"ICE006", v1.Container{LivenessProbe: lintTestProbe("live"), ReadinessProbe: lintTestProbe("ready")}, TypeIDContainer, 0},
+	{"ICE006", v1.Container{LivenessProbe: lintTestProbe("live")}, TypeIDContainer, 0},
+	{"ICE007", v1.Container{LivenessProbe: &v1.Probe{TimeoutSeconds: 10}}, TypeIDContainer, 1},
+	{"ICE007", v1.Container{LivenessProbe: &v1.Probe{TimeoutSeconds: 5, PeriodSeconds: 5}}, TypeIDContainer, 1},
+	{"ICE007", v1.Container{LivenessProbe: &v1.Probe{}, ReadinessProbe: &v1.Probe{TimeoutSeconds: 2, PeriodSeconds: 5}}, TypeIDContainer, 0},
+	{"ICE008", v1.Container{}, TypeIDContainer, 1},
+	{"ICE008", v1.Container{SecurityContext: &v1.SecurityContext{}}, TypeIDContainer, 1},
+	{"ICE008", v1.Container{SecurityContext: &v1.SecurityContext{ReadOnlyRootFilesystem: &lintReadOnly}}, TypeIDContainer, 0},
+	{"ICE009", v1.Container{}, TypeIDInitContainer, 1},
+	{"ICE009", v1.Container{}, TypeIDContainer, 0},
+	{"ICE009", testContainer("100m", ""), TypeIDInitContainer, 0},
+}
+
+func TestLintRules(t *testing.T) {
+
+	for _, test := range lintRuleTests {
+		rule := lintRuleByID(t, test.id)
+		info := BuilderInformation{ContainerType: test.containerType}
+		if output := rule.check(v1.Pod{}, test.container, info); len(output) != test.expected {
+			t.Errorf("Output %s %v not equal to expected %d finding(s)", test.id, output, test.expected)
+		}
+	}
+
+}
+
+// *************
+// lintBuildRows
+// *************
+func TestLintDisableAnnotation(t *testing.T) {
+	s := lint{Rules: lintRules}
+
+	info := BuilderInformation{ContainerType: TypeIDContainer}
+	info.Data.pod.Name = "web"
+	info.Data.pod.Annotations = map[string]string{lintDisableAnnotation: "ice001, ICE005,ICE008"}
+
+	rows := s.lintBuildRows(v1.Container{Name: "app", Image: "nginx"}, info)
+
+	// only the image tag rule is left to fail
+	if len(rows) != 1 || rows[0][0].text != "ICE003" {
+		t.Fatalf("Output %v not equal to expected a single ICE003 finding", rows)
+	}
+	if len(s.Checked) != 1 || len(s.Checked[0].results) != 1 {
+		t.Errorf("Output %v not equal to expected a single checked result", s.Checked)
+	}
+
+}
+
+func TestLintEphemeralContainer(t *testing.T) {
+	s := lint{Rules: lintRules}
+
+	info := BuilderInformation{ContainerType: TypeIDEphemeralContainer}
+	container := v1.EphemeralContainer{EphemeralContainerCommon: v1.EphemeralContainerCommon{Name: "debugger", Image: "busybox"}}
+
+	rows, err := s.BuildEphemeralContainerSpec(container, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// ephemeral containers cant set resources or probes so only the image rules are run
+	if len(rows) != 1 || rows[0][0].text != "ICE003" {
+		t.Errorf("Output %v not equal to expected a single ICE003 finding", rows)
+	}
+
+}
+
+// ****************
+// lint BuildBranch
+// ****************
+func TestLintBuildBranch(t *testing.T) {
+	s := lint{Rules: lintRules}
+
+	info := BuilderInformation{ContainerType: TypeIDContainer}
+	podRows := s.lintBuildRows(v1.Container{Name: "app", Image: "nginx"}, info)
+
+	pod, _ := s.BuildBranch(BuilderInformation{TypeName: TypeNamePod}, podRows)
+	if pod[2].number != int64(len(podRows)) {
+		t.Errorf("Output %d not equal to expected %d", pod[2].number, len(podRows))
+	}
+	if pod[1].text != severityError {
+		t.Errorf("Output %s not equal to expected %s", pod[1].text, severityError)
+	}
+
+	// owners add up the findings of each pod not the number of pods
+	owner, _ := s.BuildBranch(BuilderInformation{TypeName: "ReplicaSet"}, [][]Cell{pod, pod})
+	if owner[2].number != pod[2].number*2 {
+		t.Errorf("Output %d not equal to expected %d", owner[2].number, pod[2].number*2)
+	}
+
+}
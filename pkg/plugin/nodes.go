@@ -0,0 +1,211 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var nodesShort = "Show allocatable, requested, limits and used cpu or memory of each node"
+
+var nodesDescription = ` Prints the allocatable amount of the chosen resource for each node along with the total effective
+requests and limits of all the pods running on that node. %REQUESTED shows how much of the allocatable
+amount has been requested and %OVERCOMMIT shows how far the limits exceed the allocatable amount.
+
+Only pods that are still running or pending are counted as finished pods no longer hold any resources. A
+node with a pod that has no limit shows its LIMITS as no limit.
+
+Pods from all namespaces are counted unless a namespace is specified, the first argument is always the
+resource type (cpu or memory) any other arguments are treated as node names.`
+
+var nodesExample = `  # List cpu allocation of all nodes
+  %[1]s nodes cpu
+
+  # List memory allocation of a single node shown in gigabytes
+  %[1]s nodes memory my-node-1 --size Gi
+
+  # List cpu allocation of all nodes only counting pods from the kube-system namespace
+  %[1]s nodes cpu -n kube-system
+
+  # List nodes memory allocation with the most overcommitted nodes first
+  %[1]s nodes memory --sort '!%%OVERCOMMIT'
+
+  # List nodes that have requested more than 80%% of their allocatable cpu
+  %[1]s nodes cpu -m '%%REQUESTED>80'`
+
+func Nodes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+	var nodeNames []string
+
+	log := logger{location: "Nodes"}
+	log.Debug("Start")
+
+	if len(args) == 0 {
+		return errors.New("error: a resource type is required, either cpu or memory")
+	}
+
+	resourceType := args[0]
+	if resourceType == "mem" {
+		resourceType = "memory"
+	}
+	if resourceType != "cpu" && resourceType != "memory" {
+		return fmt.Errorf("error: unknown resource type %s, only cpu and memory are supported", resourceType)
+	}
+	nodeNames = args[1:]
+
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+
+	if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+		return errors.New("error: node allocation needs a live cluster and can not be shown when reading from a file")
+	}
+
+	// every pod on the node counts towards its allocation
+	if len(*kubeFlags.Namespace) == 0 {
+		commonFlagList.allNamespaces = true
+	}
+	commonFlagList.showTreeView = true
+	commonFlagList.showNodeTree = true
+
+	// the match filter applies to the node table not the tree used to total each node
+	nodeFilter := RowBuilder{FilterList: commonFlagList.filterList}
+	commonFlagList.filterList = map[string]matchValue{}
+	connect.Flags = commonFlagList
+
+	loopinfo := resource{}
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	loopinfo.ResourceType = resourceType
+	loopinfo.Connection = &connect
+	loopinfo.ShowAllocatable = true
+	loopinfo.ShowNodeTree = true
+	handler := getResourceHandler(resourceType)
+
+	if handler.usage != nil {
+		if err := connect.LoadMetricConfig(kubeFlags); err != nil {
+			return err
+		}
+		podStateList, err := connect.GetMetricPods([]string{})
+		if err != nil {
+			log.Tell(err)
+		} else {
+			loopinfo.MetricsResource = loopinfo.podMetrics2Hashtable(podStateList)
+		}
+	}
+
+	if cmd.Flag("size") != nil {
+		if len(cmd.Flag("size").Value.String()) > 0 {
+			loopinfo.BytesAs = cmd.Flag("size").Value.String()
+		}
+	}
+
+	// the tree is built so the resource looper can total up each node, we only use its totals
+	builder.Table = &Table{}
+	builder.ShowTreeView = true
+	builder.ShowNodeTree = true
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	table := Table{}
+	header := []string{
+		"NODE", "ALLOCATABLE", "REQUESTED", "%REQUESTED", "LIMITS", "%OVERCOMMIT", "USED",
+	}
+	table.SetHeader(header...)
+
+	if err := nodeFilter.setTableFilter(header); err != nil {
+		return err
+	}
+
+	if len(nodeNames) == 0 {
+		for name := range loopinfo.NodeTotals {
+			nodeNames = append(nodeNames, name)
+		}
+	}
+
+	for _, name := range nodeNames {
+		var node v1.Node
+
+		totals, ok := loopinfo.NodeTotals[name]
+		if !ok {
+			// no pods are running on the node so we need to check it exists
+			node, err = connect.GetNode(name)
+			if err != nil {
+				log.Tell("skipping node", name+":", err)
+				continue
+			}
+			quantity := node.Status.Allocatable[v1.ResourceName(resourceType)]
+			_, totals.allocatable = handler.quantity(&loopinfo, &quantity)
+		}
+
+		cells := loopinfo.nodeCells(name, totals)
+		if nodeFilter.matchShouldExclude(cells) {
+			continue
+		}
+		table.AddRow(cells...)
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+}
+
+// nodeCells converts the totals of a single node into table cells
+func (s *resource) nodeCells(nodeName string, totals nodeResources) []Cell {
+	var percentRequested, percentOvercommit Cell
+	var used Cell
+
+	handler := getResourceHandler(s.ResourceType)
+
+	if totals.allocatable > 0 {
+		val := validateFloat64(float64(totals.request) / float64(totals.allocatable) * 100)
+		percentRequested = NewCellFloat(fmt.Sprintf("%.2f", val), val)
+
+		if !totals.unlimited {
+			val = validateFloat64(float64(totals.limit) / float64(totals.allocatable) * 100)
+			percentOvercommit = NewCellFloat(fmt.Sprintf("%.2f", val), val)
+		}
+	}
+
+	limit := NewCellInt(handler.total(s, totals.limit), totals.limit)
+	if totals.unlimited {
+		limit = NewCellText(noLimitText)
+	}
+
+	if handler.usageTotal != nil {
+		used = NewCellInt(handler.usageTotal(s, totals.used), totals.used)
+	}
+
+	return []Cell{
+		NewCellText(nodeName),
+		NewCellInt(handler.total(s, totals.allocatable), totals.allocatable),
+		NewCellInt(handler.total(s, totals.request), totals.request),
+		percentRequested,
+		limit,
+		percentOvercommit,
+		used,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
@@ -37,6 +38,8 @@ type commonFlags struct {
 func InitSubCommands(rootCmd *cobra.Command) {
 	var includeInitShort string = "include init container(s) in the output, by default init containers are hidden"
 	var odditiesShort string = "show only the outlier rows that dont fall within the computed range"
+	var defaultsShort string = "mark requests and limits that were not in the owners pod template, normally set by a LimitRange"
+	var imageConfigDirShort string = "directory of OCI image layouts or docker save output used to read the image defaults"
 	var sizeShort string = "allows conversion to the selected size rather then the default megabyte output"
 	// var treeShort string = "Display tree like view instead of the standard list"
 
@@ -63,6 +66,9 @@ func InitSubCommands(rootCmd *cobra.Command) {
 		},
 	}
 	KubernetesConfigFlags.AddFlags(cmdCapabilities.Flags())
+	cmdCapabilities.Flags().BoolP("effective", "", false, "show the effective set of capabilities each container process gets")
+	cmdCapabilities.Flags().String("runtime", "containerd", "container runtime whose default capabilities are used with --effective, either containerd or crio")
+	cmdCapabilities.Flags().String("default-caps", "", "comma seperated list of default capabilities to use with --effective instead of the runtime defaults")
 	addCommonFlags(cmdCapabilities)
 	rootCmd.AddCommand(cmdCapabilities)
 
@@ -83,9 +89,54 @@ func InitSubCommands(rootCmd *cobra.Command) {
 		},
 	}
 	KubernetesConfigFlags.AddFlags(cmdCommands.Flags())
+	cmdCommands.Flags().String("image-config-dir", "", imageConfigDirShort)
 	addCommonFlags(cmdCommands)
 	rootCmd.AddCommand(cmdCommands)
 
+	// conditions
+	var cmdConditions = &cobra.Command{
+		Use:     "conditions",
+		Short:   conditionsShort,
+		Long:    fmt.Sprintf("%s\n\n%s", conditionsShort, conditionsDescription),
+		Example: fmt.Sprintf(conditionsExample, rootCmd.CommandPath()),
+		Aliases: []string{"condition", "cond"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Conditions(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdConditions.Flags())
+	addCommonFlags(cmdConditions)
+	rootCmd.AddCommand(cmdConditions)
+
+	// cost
+	var cmdCost = &cobra.Command{
+		Use:     "cost",
+		Short:   costShort,
+		Long:    fmt.Sprintf("%s\n\n%s", costShort, costDescription),
+		Example: fmt.Sprintf(costExample, rootCmd.CommandPath()),
+		Aliases: []string{"costs"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Cost(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdCost.Flags())
+	cmdCost.Flags().BoolP("include-init", "i", false, includeInitShort)
+	cmdCost.Flags().String("prices", "", "yaml file listing the per vCPU hour and per GiB hour prices")
+	cmdCost.Flags().String("by", "requests", "work out the cost from either the containers requests or usage")
+	cmdCost.Flags().String("size", "Gi", sizeShort)
+	addCommonFlags(cmdCost)
+	rootCmd.AddCommand(cmdCost)
+
 	// cpu
 	var cmdCPU = &cobra.Command{
 		Use:     "cpu",
@@ -105,9 +156,30 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	cmdCPU.Flags().BoolP("include-init", "i", false, includeInitShort)
 	cmdCPU.Flags().BoolP("oddities", "", false, odditiesShort)
 	cmdCPU.Flags().BoolP("raw", "r", false, "show raw values")
+	cmdCPU.Flags().BoolP("defaults", "", false, defaultsShort)
 	addCommonFlags(cmdCPU)
 	rootCmd.AddCommand(cmdCPU)
 
+	// diagnose
+	var cmdDiagnose = &cobra.Command{
+		Use:     "diagnose",
+		Short:   diagnoseShort,
+		Long:    fmt.Sprintf("%s\n\n%s", diagnoseShort, diagnoseDescription),
+		Example: fmt.Sprintf(diagnoseExample, rootCmd.CommandPath()),
+		Aliases: []string{"why"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Diagnose(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdDiagnose.Flags())
+	addCommonFlags(cmdDiagnose)
+	rootCmd.AddCommand(cmdDiagnose)
+
 	// environment
 	var cmdEnvironment = &cobra.Command{
 		Use:     "environment",
@@ -129,6 +201,48 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	addCommonFlags(cmdEnvironment)
 	rootCmd.AddCommand(cmdEnvironment)
 
+	// events
+	var cmdEvents = &cobra.Command{
+		Use:     "events",
+		Short:   eventsShort,
+		Long:    fmt.Sprintf("%s\n\n%s", eventsShort, eventsDescription),
+		Example: fmt.Sprintf(eventsExample, rootCmd.CommandPath()),
+		Aliases: []string{"event", "ev"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Events(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdEvents.Flags())
+	cmdEvents.Flags().String("since", "", "only show events seen within the duration, for example 30m or 1h")
+	cmdEvents.Flags().BoolP("warnings-only", "", false, "only show Warning events")
+	addCommonFlags(cmdEvents)
+	rootCmd.AddCommand(cmdEvents)
+
+	// host
+	var cmdHost = &cobra.Command{
+		Use:     "host",
+		Short:   hostShort,
+		Long:    fmt.Sprintf("%s\n\n%s", hostShort, hostDescription),
+		Example: fmt.Sprintf(hostExample, rootCmd.CommandPath()),
+		Aliases: []string{"hosts"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Host(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdHost.Flags())
+	addCommonFlags(cmdHost)
+	rootCmd.AddCommand(cmdHost)
+
 	// ip
 	var cmdIP = &cobra.Command{
 		Use:     "ip",
@@ -166,6 +280,9 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	}
 	KubernetesConfigFlags.AddFlags(cmdImage.Flags())
 	cmdImage.Flags().BoolP("id", "", false, "Show running containers id")
+	cmdImage.Flags().BoolP("running", "", false, "Show the image the container is running and if it has drifted from the spec")
+	cmdImage.Flags().String("drift", "", "Show image drift between pods of the same workload, or by repository with --drift=repository")
+	cmdImage.Flags().Lookup("drift").NoOptDefVal = "workload"
 	addCommonFlags(cmdImage)
 	rootCmd.AddCommand(cmdImage)
 
@@ -189,6 +306,29 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	addCommonFlags(cmdLifecycle)
 	rootCmd.AddCommand(cmdLifecycle)
 
+	// lint
+	var cmdLint = &cobra.Command{
+		Use:     "lint",
+		Short:   lintShort,
+		Long:    fmt.Sprintf("%s\n\n%s", lintShort, lintDescription),
+		Example: fmt.Sprintf(lintExample, rootCmd.CommandPath()),
+		Aliases: []string{"check"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Lint(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdLint.Flags())
+	cmdLint.Flags().String("enable", "", "comma seperated list of rule ids to run, all other rules are skipped")
+	cmdLint.Flags().String("disable", "", "comma seperated list of rule ids to skip")
+	cmdLint.Flags().String("fail-on", "", "exit with an error when a finding has this severity or above, one of note, warning or error")
+	addCommonFlags(cmdLint)
+	rootCmd.AddCommand(cmdLint)
+
 	// memory
 	var cmdMemory = &cobra.Command{
 		Use:     "memory",
@@ -211,9 +351,31 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	cmdMemory.Flags().BoolP("oddities", "", false, odditiesShort)
 	cmdMemory.Flags().BoolP("raw", "r", false, "show raw values")
 	cmdMemory.Flags().String("size", "Mi", sizeShort)
+	cmdMemory.Flags().BoolP("defaults", "", false, defaultsShort)
 	addCommonFlags(cmdMemory)
 	rootCmd.AddCommand(cmdMemory)
 
+	// nodes
+	var cmdNodes = &cobra.Command{
+		Use:     "nodes",
+		Short:   nodesShort,
+		Long:    fmt.Sprintf("%s\n\n%s", nodesShort, nodesDescription),
+		Example: fmt.Sprintf(nodesExample, rootCmd.CommandPath()),
+		Aliases: []string{"node"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Nodes(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdNodes.Flags())
+	cmdNodes.Flags().String("size", "Mi", sizeShort)
+	addCommonFlags(cmdNodes)
+	rootCmd.AddCommand(cmdNodes)
+
 	// ports
 	var cmdPorts = &cobra.Command{
 		Use:     "ports",
@@ -251,9 +413,124 @@ func InitSubCommands(rootCmd *cobra.Command) {
 		},
 	}
 	KubernetesConfigFlags.AddFlags(cmdProbes.Flags())
+	cmdProbes.Flags().BoolP("validate", "", false, "check probe ports and timings and show any problems in the STATUS column")
+	cmdProbes.Flags().BoolP("events", "", false, "show recent probe failures from the pods events")
 	addCommonFlags(cmdProbes)
 	rootCmd.AddCommand(cmdProbes)
 
+	// pss
+	var cmdPSS = &cobra.Command{
+		Use:     "pss",
+		Short:   pssShort,
+		Long:    fmt.Sprintf("%s\n\n%s", pssShort, pssDescription),
+		Example: fmt.Sprintf(pssExample, rootCmd.CommandPath()),
+		Aliases: []string{"pod-security"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := PSS(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdPSS.Flags())
+	cmdPSS.Flags().String("level", "", "only show containers that fail to meet this level, one of privileged, baseline or restricted")
+	addCommonFlags(cmdPSS)
+	rootCmd.AddCommand(cmdPSS)
+
+	// quota
+	var cmdQuota = &cobra.Command{
+		Use:     "quota",
+		Short:   quotaShort,
+		Long:    fmt.Sprintf("%s\n\n%s", quotaShort, quotaDescription),
+		Example: fmt.Sprintf(quotaExample, rootCmd.CommandPath()),
+		Aliases: []string{"quotas"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Quota(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdQuota.Flags())
+	addCommonFlags(cmdQuota)
+	rootCmd.AddCommand(cmdQuota)
+
+	// rbac
+	var cmdRBAC = &cobra.Command{
+		Use:     "rbac",
+		Short:   rbacShort,
+		Long:    fmt.Sprintf("%s\n\n%s", rbacShort, rbacDescription),
+		Example: fmt.Sprintf(rbacExample, rootCmd.CommandPath()),
+		Aliases: []string{"sa"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := RBAC(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdRBAC.Flags())
+	cmdRBAC.Flags().BoolP("verbose", "", false, "list each rule granted to the ServiceAccount")
+	addCommonFlags(cmdRBAC)
+	rootCmd.AddCommand(cmdRBAC)
+
+	// recommend
+	var cmdRecommend = &cobra.Command{
+		Use:     "recommend",
+		Short:   recommendShort,
+		Long:    fmt.Sprintf("%s\n\n%s", recommendShort, recommendDescription),
+		Example: fmt.Sprintf(recommendExample, rootCmd.CommandPath()),
+		Aliases: []string{"rec"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Recommend(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdRecommend.Flags())
+	cmdRecommend.Flags().BoolP("include-init", "i", false, includeInitShort)
+	cmdRecommend.Flags().Int("samples", 1, "number of metric samples to take before making a recommendation")
+	cmdRecommend.Flags().Duration("interval", 15*time.Second, "time to wait between each metric sample")
+	cmdRecommend.Flags().Int("percentile", 95, "percentile of the samples used to calculate the recommended request")
+	cmdRecommend.Flags().Int("headroom", 20, "percentage added to the observed usage before rounding")
+	cmdRecommend.Flags().String("round", "", "round recommendations up to a multiple of this value, defaults to 50m for cpu and 64Mi for memory")
+	cmdRecommend.Flags().String("size", "Mi", sizeShort)
+	addCommonFlags(cmdRecommend)
+	rootCmd.AddCommand(cmdRecommend)
+
+	// resource
+	var cmdResource = &cobra.Command{
+		Use:     "resource",
+		Short:   resourceNameShort,
+		Long:    fmt.Sprintf("%s\n\n%s", resourceNameShort, resourceNameDescription),
+		Example: fmt.Sprintf(resourceNameExample, rootCmd.CommandPath()),
+		Aliases: []string{"res"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := ResourceByName(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdResource.Flags())
+	cmdResource.Flags().BoolP("include-init", "i", false, includeInitShort)
+	cmdResource.Flags().BoolP("raw", "r", false, "show raw values")
+	cmdResource.Flags().String("size", "Mi", sizeShort)
+	cmdResource.Flags().BoolP("defaults", "", false, defaultsShort)
+	addCommonFlags(cmdResource)
+	rootCmd.AddCommand(cmdResource)
+
 	// restarts
 	var cmdRestart = &cobra.Command{
 		Use:     "restarts",
@@ -294,6 +571,8 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	}
 	KubernetesConfigFlags.AddFlags(cmdSecurity.Flags())
 	cmdSecurity.Flags().BoolP("selinux", "", false, "show the SELinux context thats applied to the containers")
+	cmdSecurity.Flags().BoolP("extended", "", false, "show the seccomp, AppArmor, procMount, windows, sysctl and group settings")
+	cmdSecurity.Flags().String("image-config-dir", "", imageConfigDirShort)
 	addCommonFlags(cmdSecurity)
 	rootCmd.AddCommand(cmdSecurity)
 
@@ -325,6 +604,50 @@ func InitSubCommands(rootCmd *cobra.Command) {
 	addCommonFlags(cmdStatus)
 	rootCmd.AddCommand(cmdStatus)
 
+	// storage
+	var cmdStorage = &cobra.Command{
+		Use:     "storage",
+		Short:   storageShort,
+		Long:    fmt.Sprintf("%s\n\n%s", storageShort, storageDescription),
+		Example: fmt.Sprintf(storageExample, rootCmd.CommandPath()),
+		Aliases: []string{"store"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Resources(cmd, KubernetesConfigFlags, args, "ephemeral-storage"); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdStorage.Flags())
+	cmdStorage.Flags().BoolP("include-init", "i", false, includeInitShort)
+	cmdStorage.Flags().BoolP("raw", "r", false, "show raw values")
+	cmdStorage.Flags().String("size", "Mi", sizeShort)
+	cmdStorage.Flags().BoolP("defaults", "", false, defaultsShort)
+	addCommonFlags(cmdStorage)
+	rootCmd.AddCommand(cmdStorage)
+
+	// timeline
+	var cmdTimeline = &cobra.Command{
+		Use:     "timeline",
+		Short:   timelineShort,
+		Long:    fmt.Sprintf("%s\n\n%s", timelineShort, timelineDescription),
+		Example: fmt.Sprintf(timelineExample, rootCmd.CommandPath()),
+		Aliases: []string{"startup"},
+		// SuggestFor: []string{""},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := Timeline(cmd, KubernetesConfigFlags, args); err != nil {
+				return err
+			}
+
+			return nil
+		},
+	}
+	KubernetesConfigFlags.AddFlags(cmdTimeline.Flags())
+	addCommonFlags(cmdTimeline)
+	rootCmd.AddCommand(cmdTimeline)
+
 	// version
 	var cmdVersion = &cobra.Command{
 		Use:   "version",
@@ -372,7 +695,7 @@ func addCommonFlags(cmdObj *cobra.Command) {
 	cmdObj.Flags().BoolP("show-namespace", "", false, `Show the namespace column`)
 	cmdObj.Flags().BoolP("show-node", "", false, `Show the node name column`)
 	cmdObj.Flags().BoolP("show-type", "T", false, `Show the container type column, where:
-    I=init container, C=container, E=ephemerial container, P=Pod, D=Deployment, R=ReplicaSet, A=DaemonSet, S=StatefulSet, N=Node`)
+    I=init container, C=container, E=ephemerial container, P=Pod, D=Deployment, R=ReplicaSet, A=DaemonSet, S=StatefulSet, N=Node, M=Namespace`)
 	cmdObj.Flags().BoolP("tree", "t", false, `Display tree like view instead of the standard list`)
 	cmdObj.Flags().BoolP("node-tree", "", false, `Displayes the tree with the nodes as the root`)
 	cmdObj.Flags().StringP("node-label", "", "", `Show the selected node label as a column`)
@@ -430,8 +753,26 @@ func processCommonFlags(cmd *cobra.Command) (commonFlags, error) {
 				f.outputAs = "json"
 			case "yaml":
 				f.outputAs = "yaml"
+			case "patch":
+				// only the recommend command knows how to build a patch
+				if cmd.Name() != "recommend" {
+					return commonFlags{}, errors.New("patch output is only supported by the recommend command")
+				}
+				f.outputAs = "patch"
+			case "sarif":
+				// sarif is only used to report lint results
+				if cmd.Name() != "lint" {
+					return commonFlags{}, errors.New("sarif output is only supported by the lint command")
+				}
+				f.outputAs = "sarif"
+			case "junit":
+				// junit is only used to report the results of checks
+				if cmd.Name() != "lint" && cmd.Name() != "pss" {
+					return commonFlags{}, errors.New("junit output is only supported by the lint and pss commands")
+				}
+				f.outputAs = "junit"
 			default:
-				return commonFlags{}, errors.New("unknown output format only csv, list, json and yaml are supported")
+				return commonFlags{}, errors.New("unknown output format only csv, list, json, yaml, patch, sarif and junit are supported")
 			}
 		}
 	}
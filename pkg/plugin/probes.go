@@ -1,11 +1,15 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
+	duration "k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 )
 
@@ -14,7 +18,19 @@ var probesShort = "Shows details of configured startup, readiness and liveness p
 var probesDescription = ` Prints details of the currently configured startup, liveness and rediness probes for each 
 container. Details like the delay timeout and action are printed along with the configured probe
 type. If no name is specified the container probe details of all pods in the current namespace
-are shown.`
+are shown.
+
+HTTPGet probes show the scheme, host, port, path and any headers, GRPC probes show the port and service name.
+GRACE is the terminationGracePeriodSeconds set on the probe itself.
+
+Use --validate to check each probe, the STATUS column shows any problems found or ok when there are none.
+HTTPGet, TCPSocket and GRPC probes are checked to make sure their port is declared by the container and is
+not a port of another container. Startup probes are checked against the start time of the container, the
+budget is initialDelaySeconds + failureThreshold x periodSeconds using the api defaults of 3 and 10 when they
+are not set. Liveness probes without an initial delay or startup probe are marked.
+
+Use --events to show how long ago each probe last failed, how many times it has failed and the last failure
+message, these are read from the Unhealthy events of the pod so only recent failures are shown.`
 
 var probesExample = `  # List containers probe info from pods
   %[1]s probes
@@ -41,7 +57,13 @@ var probesExample = `  # List containers probe info from pods
   %[1]s probes -l app=web
 
   # List container probe info from all pods where the pod label app is either web or mail
-  %[1]s probes -l "app in (web,mail)"`
+  %[1]s probes -l "app in (web,mail)"
+
+  # List probes that point at the wrong port or are likely to restart the container
+  %[1]s probes --validate -m "STATUS!=ok"
+
+  # List probes that have been failing
+  %[1]s probes --events -m "FAILURE-COUNT>0"`
 
 type probeAction struct {
 	probeName  string
@@ -76,6 +98,22 @@ func Probes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args [
 
 	builder.SetFlagsFrom(commonFlagList)
 
+	if cmd.Flag("validate").Value.String() == "true" {
+		loopinfo.Validate = true
+	}
+
+	if cmd.Flag("events").Value.String() == "true" {
+		stdinChanged, err := builder.HasStdinChanged()
+		if err != nil {
+			return err
+		}
+		if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+			return errors.New("error: probe events can only be shown from a live cluster")
+		}
+		loopinfo.ShowEvents = true
+		loopinfo.Connection = &connect
+	}
+
 	table := Table{}
 	builder.Table = &table
 	builder.ShowTreeView = commonFlagList.showTreeView
@@ -94,6 +132,9 @@ func Probes(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args [
 }
 
 type probes struct {
+	Connection *Connector
+	Validate   bool
+	ShowEvents bool
 }
 
 func (s *probes) Headers() []string {
@@ -104,8 +145,13 @@ func (s *probes) Headers() []string {
 		"TIMEOUT",
 		"SUCCESS",
 		"FAILURE",
+		"GRACE",
 		"CHECK",
 		"ACTION",
+		"STATUS",
+		"LAST-FAILURE",
+		"FAILURE-COUNT",
+		"LAST-MESSAGE",
 	}
 }
 
@@ -118,7 +164,17 @@ func (s *probes) BuildEphemeralContainerStatus(container v1.ContainerStatus, inf
 }
 
 func (s *probes) HideColumns(info BuilderInformation) []int {
-	return []int{}
+	var hideColumns []int
+
+	if !s.Validate {
+		hideColumns = append(hideColumns, 9)
+	}
+
+	if !s.ShowEvents {
+		hideColumns = append(hideColumns, 10, 11, 12)
+	}
+
+	return hideColumns
 }
 
 func (s *probes) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
@@ -131,29 +187,47 @@ func (s *probes) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, er
 		NewCellText(""),
 		NewCellText(""),
 		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
+		NewCellText(""),
 	}
 	return out, nil
 }
 
 func (s *probes) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
 	out := [][]Cell{}
-	probeList := s.buildProbeList(container)
-	for _, probe := range probeList {
-		for _, action := range probe {
-			out = append(out, s.probesBuildRow(info, action))
+	for _, action := range s.buildProbeList(container) {
+		row := s.probesBuildRow(info, action)
+		if s.Validate {
+			row[9] = NewCellText(s.validateProbe(info, container, action))
 		}
+		if s.ShowEvents {
+			cells, err := s.probeEventCells(info, container.Name, action.probeName)
+			if err != nil {
+				return [][]Cell{}, err
+			}
+			copy(row[10:], cells)
+		}
+		out = append(out, row)
 	}
 	return out, nil
 }
 
 func (s *probes) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
-	out := [][]Cell{}
-	return out, nil
+	// ephemeral containers share the same fields as a standard container
+	return s.BuildContainerSpec(v1.Container(container.EphemeralContainerCommon), info)
 }
 
 func (s *probes) probesBuildRow(info BuilderInformation, action probeAction) []Cell {
 	var cellList []Cell
 
+	grace := Cell{}
+	if action.probe.TerminationGracePeriodSeconds != nil {
+		grace = NewCellInt(fmt.Sprintf("%d", *action.probe.TerminationGracePeriodSeconds), *action.probe.TerminationGracePeriodSeconds)
+	}
+
 	// if info.TreeView {
 	// 	cellList = info.BuildTreeCell(cellList)
 	// }
@@ -165,24 +239,29 @@ func (s *probes) probesBuildRow(info BuilderInformation, action probeAction) []C
 		NewCellInt(fmt.Sprintf("%d", action.probe.TimeoutSeconds), int64(action.probe.TimeoutSeconds)),
 		NewCellInt(fmt.Sprintf("%d", action.probe.SuccessThreshold), int64(action.probe.SuccessThreshold)),
 		NewCellInt(fmt.Sprintf("%d", action.probe.FailureThreshold), int64(action.probe.FailureThreshold)),
+		grace,
 		NewCellText(action.actionName),
 		NewCellText(action.action),
+		Cell{},
+		Cell{},
+		Cell{},
+		Cell{},
 	)
 
 	return cellList
 }
 
-// check each type of probe and return a list
-func (s *probes) buildProbeList(container v1.Container) map[string][]probeAction {
-	probes := make(map[string][]probeAction)
+// check each type of probe and return a list, probes are always listed as startup, liveness then readiness
+func (s *probes) buildProbeList(container v1.Container) []probeAction {
+	probes := []probeAction{}
+	if container.StartupProbe != nil {
+		probes = append(probes, s.buildProbeAction("startup", container.StartupProbe)...)
+	}
 	if container.LivenessProbe != nil {
-		probes["liveness"] = s.buildProbeAction("liveness", container.LivenessProbe)
+		probes = append(probes, s.buildProbeAction("liveness", container.LivenessProbe)...)
 	}
 	if container.ReadinessProbe != nil {
-		probes["readiness"] = s.buildProbeAction("readiness", container.ReadinessProbe)
-	}
-	if container.StartupProbe != nil {
-		probes["startup"] = s.buildProbeAction("liveness", container.StartupProbe)
+		probes = append(probes, s.buildProbeAction("readiness", container.ReadinessProbe)...)
 	}
 
 	return probes
@@ -206,11 +285,14 @@ func (s *probes) buildProbeAction(name string, probe *v1.Probe) []probeAction {
 	// translate HTTP action
 	if probe.HTTPGet != nil {
 		item.actionName = "HTTPGet"
-		actionStr := ""
 		p := probe.HTTPGet
+
+		// the scheme defaults to http when not set
+		scheme := "http"
 		if len(p.Scheme) > 0 {
-			actionStr = strings.ToLower(string(p.Scheme)) + "://"
+			scheme = strings.ToLower(string(p.Scheme))
 		}
+		actionStr := scheme + "://"
 
 		if len(p.Host) > 0 {
 			actionStr += p.Host
@@ -221,6 +303,14 @@ func (s *probes) buildProbeAction(name string, probe *v1.Probe) []probeAction {
 		if len(p.Path) > 0 {
 			actionStr += p.Path
 		}
+
+		if len(p.HTTPHeaders) > 0 {
+			headers := []string{}
+			for _, header := range p.HTTPHeaders {
+				headers = append(headers, header.Name+": "+header.Value)
+			}
+			actionStr += " [" + strings.Join(headers, ", ") + "]"
+		}
 		item.action = actionStr
 		probeList = append(probeList, item)
 	}
@@ -228,11 +318,9 @@ func (s *probes) buildProbeAction(name string, probe *v1.Probe) []probeAction {
 	// translate GRPC action
 	if probe.GRPC != nil {
 		item.actionName = "GRPC"
-		if probe.GRPC.Service == nil {
-			item.action = *probe.GRPC.Service
-		}
-		if probe.GRPC.Port > 0 {
-			item.action += fmt.Sprintf(":%d", probe.GRPC.Port)
+		item.action = fmt.Sprintf(":%d", probe.GRPC.Port)
+		if probe.GRPC.Service != nil && len(*probe.GRPC.Service) > 0 {
+			item.action += " service=" + *probe.GRPC.Service
 		}
 		probeList = append(probeList, item)
 	}
@@ -241,7 +329,6 @@ func (s *probes) buildProbeAction(name string, probe *v1.Probe) []probeAction {
 	if probe.TCPSocket != nil {
 		item.actionName = "TCPSocket"
 		actionStr := ""
-		item.action = probe.TCPSocket.String()
 		if len(probe.TCPSocket.Host) > 0 {
 			actionStr += probe.TCPSocket.Host
 		}
@@ -252,3 +339,162 @@ func (s *probes) buildProbeAction(name string, probe *v1.Probe) []probeAction {
 
 	return probeList
 }
+
+// validateProbe checks the probe target and timings, a comma seperated list of problems is returned or ok
+//
+//	when there are none
+func (s *probes) validateProbe(info BuilderInformation, container v1.Container, action probeAction) string {
+	var problems []string
+
+	probe := action.probe
+
+	if port, ok := probePort(probe); ok {
+		if msg := checkProbePort(info.Data.pod, container, port); len(msg) > 0 {
+			problems = append(problems, msg)
+		}
+	}
+
+	switch action.probeName {
+	case "startup":
+		// use the api defaults when reading from a file
+		failure := probe.FailureThreshold
+		if failure == 0 {
+			failure = 3
+		}
+		period := probe.PeriodSeconds
+		if period == 0 {
+			period = 10
+		}
+		budget := time.Duration(probe.InitialDelaySeconds+failure*period) * time.Second
+
+		if started, ok := containerStartDuration(info.Data.pod, container.Name); ok && started > budget {
+			problems = append(problems, fmt.Sprintf("startup budget %s is shorter than the observed start time %s", budget, started.Round(time.Second)))
+		}
+
+	case "liveness":
+		if probe.InitialDelaySeconds == 0 && container.StartupProbe == nil {
+			problems = append(problems, "liveness probe has no initial delay or startup probe")
+		}
+	}
+
+	if len(problems) == 0 {
+		return "ok"
+	}
+	return strings.Join(problems, ", ")
+}
+
+// probePort returns the port a probe connects to, ok is false for probes that dont use a port
+func probePort(probe *v1.Probe) (intstr.IntOrString, bool) {
+	switch {
+	case probe.HTTPGet != nil:
+		return probe.HTTPGet.Port, true
+	case probe.TCPSocket != nil:
+		return probe.TCPSocket.Port, true
+	case probe.GRPC != nil:
+		return intstr.FromInt(int(probe.GRPC.Port)), true
+	}
+	return intstr.IntOrString{}, false
+}
+
+// checkProbePort makes sure the port is declared by the container, named ports must exist and numbered ports
+//
+//	are checked against the other containers in the pod
+func checkProbePort(pod v1.Pod, container v1.Container, port intstr.IntOrString) string {
+	if port.Type == intstr.String {
+		for _, p := range container.Ports {
+			if p.Name == port.StrVal {
+				return ""
+			}
+		}
+		return fmt.Sprintf("named port %s is not declared", port.StrVal)
+	}
+
+	for _, p := range container.Ports {
+		if p.ContainerPort == port.IntVal {
+			return ""
+		}
+	}
+
+	for _, list := range [][]v1.Container{pod.Spec.InitContainers, pod.Spec.Containers} {
+		for _, other := range list {
+			if other.Name == container.Name {
+				continue
+			}
+			for _, p := range other.Ports {
+				if p.ContainerPort == port.IntVal {
+					return fmt.Sprintf("port %d belongs to container %s", port.IntVal, other.Name)
+				}
+			}
+		}
+	}
+
+	return fmt.Sprintf("port %d is not declared", port.IntVal)
+}
+
+// containerStartDuration returns how long the container took to become ready, this is the time from the
+//
+//	container starting to the pods ContainersReady condition, ok is false when it cant be worked out
+func containerStartDuration(pod v1.Pod, containerName string) (time.Duration, bool) {
+	status := containerStatusByName(pod, containerName)
+	if status == nil || status.State.Running == nil || !status.Ready {
+		return 0, false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != v1.ContainersReady || condition.Status != v1.ConditionTrue {
+			continue
+		}
+
+		started := status.State.Running.StartedAt.Time
+		ready := condition.LastTransitionTime.Time
+		if ready.After(started) {
+			return ready.Sub(started), true
+		}
+	}
+
+	return 0, false
+}
+
+// probeEventCells returns the last failure, failure count and last message of a probe from the pods
+//
+//	Unhealthy events, the event message starts with the probe type for example "Readiness probe failed:"
+func (s *probes) probeEventCells(info BuilderInformation, containerName string, probeName string) ([]Cell, error) {
+	var count int64
+	var last time.Time
+	var message string
+
+	events, err := s.Connection.GetPodEvents(info.Data.pod)
+	if err != nil {
+		return []Cell{}, err
+	}
+
+	for _, event := range events {
+		if event.Reason != "Unhealthy" || eventContainerName(event) != containerName {
+			continue
+		}
+
+		words := strings.SplitN(event.Message, " ", 2)
+		if len(words) < 2 || !strings.EqualFold(words[0], probeName) || !strings.HasPrefix(words[1], "probe") {
+			continue
+		}
+
+		count += eventCount(event)
+		if seen := eventLastSeen(event); seen.After(last) {
+			last = seen
+			message = event.Message
+			if i := strings.Index(message, ": "); i >= 0 {
+				message = message[i+2:]
+			}
+		}
+	}
+
+	if count == 0 {
+		return []Cell{Cell{}, NewCellInt("0", 0), Cell{}}, nil
+	}
+
+	return []Cell{
+		NewCellText(duration.HumanDuration(time.Since(last))),
+		NewCellInt(fmt.Sprintf("%d", count), count),
+		NewCellText(strings.TrimSpace(message)),
+	}, nil
+}
@@ -0,0 +1,230 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func probeTestPod() v1.Pod {
+	return v1.Pod{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{
+			{Name: "setup", Ports: []v1.ContainerPort{{ContainerPort: 9000}}},
+		},
+		Containers: []v1.Container{
+			{Name: "web", Ports: []v1.ContainerPort{{Name: "http", ContainerPort: 8080}}},
+			{Name: "proxy", Ports: []v1.ContainerPort{{Name: "admin", ContainerPort: 15000}}},
+		},
+	}}
+}
+
+// *********
+// probePort
+// *********
+type probePortTest struct {
+	arg1       *v1.Probe
+	expected   intstr.IntOrString
+	expectedOk bool
+}
+
+var probePortTests = []probePortTest{
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Port: intstr.FromString("http")}}}, intstr.FromString("http"), true},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(8080)}}}, intstr.FromInt(8080), true},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{GRPC: &v1.GRPCAction{Port: 9090}}}, intstr.FromInt(9090), true},
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{Command: []string{"true"}}}}, intstr.IntOrString{}, false},
+}
+
+func TestProbePort(t *testing.T) {
+
+	for _, test := range probePortTests {
+		output, ok := probePort(test.arg1)
+		if ok != test.expectedOk || output != test.expected {
+			t.Errorf("Output %s %t not equal to expected %s %t", output.String(), ok, test.expected.String(), test.expectedOk)
+		}
+	}
+
+}
+
+// **************
+// checkProbePort
+// **************
+type checkProbePortTest struct {
+	container int
+	port      intstr.IntOrString
+	expected  string
+}
+
+var checkProbePortTests = []checkProbePortTest{
+	{0, intstr.FromString("http"), ""},
+	{0, intstr.FromInt(8080), ""},
+	{0, intstr.FromString("admin"), "named port admin is not declared"},
+	{0, intstr.FromInt(15000), "port 15000 belongs to container proxy"},
+	{0, intstr.FromInt(9000), "port 9000 belongs to container setup"},
+	{0, intstr.FromInt(8081), "port 8081 is not declared"},
+	{1, intstr.FromInt(8080), "port 8080 belongs to container web"},
+}
+
+func TestCheckProbePort(t *testing.T) {
+	pod := probeTestPod()
+
+	for _, test := range checkProbePortTests {
+		container := pod.Spec.Containers[test.container]
+		if output := checkProbePort(pod, container, test.port); output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+
+}
+
+// *************
+// validateProbe
+// *************
+type validateProbeTest struct {
+	probeName string
+	probe     *v1.Probe
+	startup   bool // container also has a startup probe
+	expected  string
+}
+
+var validateProbeTests = []validateProbeTest{
+	{"readiness", &v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Port: intstr.FromString("http")}}}, false, "ok"},
+	{"readiness", &v1.Probe{ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{}}}, false, "ok"},
+	{"readiness", &v1.Probe{ProbeHandler: v1.ProbeHandler{TCPSocket: &v1.TCPSocketAction{Port: intstr.FromInt(81)}}}, false,
+		"port 81 is not declared"},
+	{"liveness", &v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Port: intstr.FromInt(8080)}}}, false,
+		"liveness probe has no initial delay or startup probe"},
+	{"liveness", &v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Port: intstr.FromInt(8080)}}}, true, "ok"},
+	{"liveness", &v1.Probe{InitialDelaySeconds: 10, ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Port: intstr.FromInt(8080)}}}, false, "ok"},
+	{"liveness", &v1.Probe{ProbeHandler: v1.ProbeHandler{GRPC: &v1.GRPCAction{Port: 15000}}}, false,
+		"port 15000 belongs to container proxy, liveness probe has no initial delay or startup probe"},
+	{"startup", &v1.Probe{ProbeHandler: v1.ProbeHandler{HTTPGet: &v1.HTTPGetAction{Port: intstr.FromString("http")}}}, false, "ok"},
+}
+
+func TestValidateProbe(t *testing.T) {
+	s := probes{}
+	info := BuilderInformation{}
+	info.Data.pod = probeTestPod()
+
+	for _, test := range validateProbeTests {
+		container := info.Data.pod.Spec.Containers[0]
+		if test.startup {
+			container.StartupProbe = &v1.Probe{}
+		}
+
+		action := probeAction{probeName: test.probeName, probe: test.probe}
+		if output := s.validateProbe(info, container, action); output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+
+}
+
+// ******************
+// startup validation
+// ******************
+type validateStartupProbeTest struct {
+	probe    *v1.Probe
+	expected string
+}
+
+var validateStartupProbeTests = []validateStartupProbeTest{
+	// the api defaults give a budget of 30s
+	{&v1.Probe{ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{}}}, "startup budget 30s is shorter than the observed start time 45s"},
+	{&v1.Probe{PeriodSeconds: 5, FailureThreshold: 6, ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{}}}, "startup budget 30s is shorter than the observed start time 45s"},
+	{&v1.Probe{InitialDelaySeconds: 20, ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{}}}, "ok"},
+	{&v1.Probe{FailureThreshold: 10, ProbeHandler: v1.ProbeHandler{Exec: &v1.ExecAction{}}}, "ok"},
+}
+
+func TestValidateStartupProbe(t *testing.T) {
+	started := time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	s := probes{}
+	info := BuilderInformation{}
+	info.Data.pod = probeTestPod()
+	info.Data.pod.Status = v1.PodStatus{
+		Conditions: []v1.PodCondition{
+			{Type: v1.ContainersReady, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(started.Add(45 * time.Second))},
+		},
+		ContainerStatuses: []v1.ContainerStatus{{
+			Name:  "web",
+			Ready: true,
+			State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(started)}},
+		}},
+	}
+	container := info.Data.pod.Spec.Containers[0]
+
+	for _, test := range validateStartupProbeTests {
+		action := probeAction{probeName: "startup", probe: test.probe}
+		if output := s.validateProbe(info, container, action); output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+
+	// containers that are not ready yet have no start time to check against
+	info.Data.pod.Status.ContainerStatuses[0].Ready = false
+	action := probeAction{probeName: "startup", probe: validateStartupProbeTests[0].probe}
+	if output := s.validateProbe(info, container, action); output != "ok" {
+		t.Errorf("Output %s not equal to expected ok", output)
+	}
+
+}
+
+// ***************
+// probeEventCells
+// ***************
+func probeTestEvent(fieldPath string, reason string, message string, count int32, age time.Duration) v1.Event {
+	return v1.Event{
+		InvolvedObject: v1.ObjectReference{FieldPath: fieldPath},
+		Reason:         reason,
+		Message:        message,
+		Count:          count,
+		LastTimestamp:  metav1.NewTime(time.Now().Add(-age)),
+	}
+}
+
+type probeEventCellsTest struct {
+	container       string
+	probeName       string
+	expectedCount   int64
+	expectedMessage string
+}
+
+var probeEventCellsTests = []probeEventCellsTest{
+	// errored probes are counted as failures
+	{"web", "readiness", 6, "HTTP probe failed with statuscode: 503"},
+	{"web", "liveness", 1, "Get \"http://10.0.0.1:8080/healthz\": context deadline exceeded"},
+	{"web", "startup", 0, ""},
+	{"proxy", "readiness", 2, "dial tcp 10.0.0.1:15000: connect: connection refused"},
+}
+
+func TestProbeEventCells(t *testing.T) {
+	info := BuilderInformation{}
+	info.Data.pod.UID = "probe-test"
+
+	s := probes{Connection: &Connector{eventList: map[string][]v1.Event{"probe-test": {
+		probeTestEvent("spec.containers{web}", "Unhealthy", "Readiness probe failed: connection refused", 3, time.Hour),
+		probeTestEvent("spec.containers{web}", "Unhealthy", "Readiness probe failed: HTTP probe failed with statuscode: 503", 2, time.Minute),
+		probeTestEvent("spec.containers{web}", "Unhealthy", "Liveness probe failed: Get \"http://10.0.0.1:8080/healthz\": context deadline exceeded", 0, time.Minute),
+		probeTestEvent("spec.containers{web}", "Unhealthy", "Readiness probe errored: rpc error", 1, time.Hour),
+		probeTestEvent("spec.containers{web}", "Killing", "Container web failed liveness probe, will be restarted", 1, time.Minute),
+		probeTestEvent("spec.containers{proxy}", "Unhealthy", "readiness probe failed: dial tcp 10.0.0.1:15000: connect: connection refused", 2, time.Minute),
+		probeTestEvent("", "Unhealthy", "Readiness probe failed: pod level", 7, time.Minute),
+	}}}}
+
+	for _, test := range probeEventCellsTests {
+		cells, err := s.probeEventCells(info, test.container, test.probeName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cells[1].number != test.expectedCount {
+			t.Errorf("Output %d not equal to expected %d for %s %s", cells[1].number, test.expectedCount, test.container, test.probeName)
+		}
+		if cells[2].text != test.expectedMessage {
+			t.Errorf("Output %s not equal to expected %s", cells[2].text, test.expectedMessage)
+		}
+	}
+
+}
@@ -0,0 +1,580 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var pssShort = "Check each container against the Pod Security Standards levels"
+
+var pssDescription = ` Checks the pod and container settings against the Kubernetes Pod Security Standards and prints the
+highest level each container meets (privileged, baseline or restricted) along with the checks that failed.
+Pod level settings like host namespaces, volumes and sysctls are included in the result of every container.
+
+Use --level to only show the containers that fail to meet the chosen level, with -o junit every container
+is reported as a testcase and each check that fails the chosen level (restricted by default) as a failure.`
+
+var pssExample = `  # Check the containers in all pods against the Pod Security Standards
+  %[1]s pss
+
+  # Check the containers of a single pod
+  %[1]s pss my-pod-4jh36
+
+  # List only the containers that dont meet the restricted level
+  %[1]s pss --level restricted
+
+  # List only the containers that dont meet the baseline level in all namespaces
+  %[1]s pss --level baseline -A
+
+  # Check a manifest file against the baseline level and output the results as JUnit XML
+  %[1]s pss -f deployment.yaml --level baseline -o junit`
+
+const (
+	pssPrivileged = "privileged"
+	pssBaseline   = "baseline"
+	pssRestricted = "restricted"
+)
+
+// pssLevels the levels in order from least to most secure
+var pssLevels = []string{pssPrivileged, pssBaseline, pssRestricted}
+
+// pssCheck is a single Pod Security Standards control, pod is used for checks against the pod spec and
+//
+//	container for checks against each container, each returns a message for every violation found
+type pssCheck struct {
+	name      string
+	level     string
+	pod       func(pod v1.Pod) []string
+	container func(pod v1.Pod, container v1.Container) []string
+}
+
+var pssBaselineCapabilities = map[v1.Capability]bool{
+	"AUDIT_WRITE": true, "CHOWN": true, "DAC_OVERRIDE": true, "FOWNER": true, "FSETID": true, "KILL": true,
+	"MKNOD": true, "NET_BIND_SERVICE": true, "SETFCAP": true, "SETGID": true, "SETPCAP": true,
+	"SETUID": true, "SYS_CHROOT": true,
+}
+
+var pssSafeSysctls = map[string]bool{
+	"kernel.shm_rmid_forced": true, "net.ipv4.ip_local_port_range": true, "net.ipv4.ip_unprivileged_port_start": true,
+	"net.ipv4.tcp_syncookies": true, "net.ipv4.ping_group_range": true,
+}
+
+var pssSELinuxTypes = map[string]bool{
+	"": true, "container_t": true, "container_init_t": true, "container_kvm_t": true,
+}
+
+var pssChecks = []pssCheck{
+	{
+		name:  "hostProcess",
+		level: pssBaseline,
+		pod: func(pod v1.Pod) []string {
+			psc := pod.Spec.SecurityContext
+			if psc != nil && psc.WindowsOptions != nil && psc.WindowsOptions.HostProcess != nil && *psc.WindowsOptions.HostProcess {
+				return []string{"pod hostProcess=true"}
+			}
+			return nil
+		},
+		container: func(pod v1.Pod, container v1.Container) []string {
+			csc := container.SecurityContext
+			if csc != nil && csc.WindowsOptions != nil && csc.WindowsOptions.HostProcess != nil && *csc.WindowsOptions.HostProcess {
+				return []string{"hostProcess=true"}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "hostNamespaces",
+		level: pssBaseline,
+		pod: func(pod v1.Pod) []string {
+			var out []string
+			if pod.Spec.HostNetwork {
+				out = append(out, "hostNetwork=true")
+			}
+			if pod.Spec.HostPID {
+				out = append(out, "hostPID=true")
+			}
+			if pod.Spec.HostIPC {
+				out = append(out, "hostIPC=true")
+			}
+			return out
+		},
+	},
+	{
+		name:  "privileged",
+		level: pssBaseline,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			csc := container.SecurityContext
+			if csc != nil && csc.Privileged != nil && *csc.Privileged {
+				return []string{"privileged=true"}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "capabilities",
+		level: pssBaseline,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			var out []string
+			csc := container.SecurityContext
+			if csc == nil || csc.Capabilities == nil {
+				return nil
+			}
+			for _, capability := range csc.Capabilities.Add {
+				if !pssBaselineCapabilities[capability] {
+					out = append(out, "capabilities add="+string(capability))
+				}
+			}
+			return out
+		},
+	},
+	{
+		name:  "hostPathVolumes",
+		level: pssBaseline,
+		pod: func(pod v1.Pod) []string {
+			var out []string
+			for _, volume := range pod.Spec.Volumes {
+				if volume.HostPath != nil {
+					out = append(out, "hostPath "+volume.Name+"="+volume.HostPath.Path)
+				}
+			}
+			return out
+		},
+	},
+	{
+		name:  "hostPorts",
+		level: pssBaseline,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			var out []string
+			for _, port := range container.Ports {
+				if port.HostPort != 0 {
+					out = append(out, fmt.Sprintf("hostPort=%d", port.HostPort))
+				}
+			}
+			return out
+		},
+	},
+	{
+		name:  "appArmor",
+		level: pssBaseline,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			profile := pod.Annotations["container.apparmor.security.beta.kubernetes.io/"+container.Name]
+			if len(profile) == 0 || profile == "runtime/default" || strings.HasPrefix(profile, "localhost/") {
+				return nil
+			}
+			return []string{"appArmor profile=" + profile}
+		},
+	},
+	{
+		name:  "seLinux",
+		level: pssBaseline,
+		pod: func(pod v1.Pod) []string {
+			if pod.Spec.SecurityContext == nil {
+				return nil
+			}
+			return pssCheckSELinux(pod.Spec.SecurityContext.SELinuxOptions)
+		},
+		container: func(pod v1.Pod, container v1.Container) []string {
+			if container.SecurityContext == nil {
+				return nil
+			}
+			return pssCheckSELinux(container.SecurityContext.SELinuxOptions)
+		},
+	},
+	{
+		name:  "procMount",
+		level: pssBaseline,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			csc := container.SecurityContext
+			if csc != nil && csc.ProcMount != nil && *csc.ProcMount != v1.DefaultProcMount {
+				return []string{"procMount=" + string(*csc.ProcMount)}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "seccomp",
+		level: pssBaseline,
+		pod: func(pod v1.Pod) []string {
+			psc := pod.Spec.SecurityContext
+			if psc != nil && psc.SeccompProfile != nil && psc.SeccompProfile.Type == v1.SeccompProfileTypeUnconfined {
+				return []string{"pod seccompProfile=Unconfined"}
+			}
+			return nil
+		},
+		container: func(pod v1.Pod, container v1.Container) []string {
+			csc := container.SecurityContext
+			if csc != nil && csc.SeccompProfile != nil && csc.SeccompProfile.Type == v1.SeccompProfileTypeUnconfined {
+				return []string{"seccompProfile=Unconfined"}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "sysctls",
+		level: pssBaseline,
+		pod: func(pod v1.Pod) []string {
+			var out []string
+			if pod.Spec.SecurityContext == nil {
+				return nil
+			}
+			for _, sysctl := range pod.Spec.SecurityContext.Sysctls {
+				if !pssSafeSysctls[sysctl.Name] {
+					out = append(out, "sysctl "+sysctl.Name)
+				}
+			}
+			return out
+		},
+	},
+	{
+		name:  "volumeTypes",
+		level: pssRestricted,
+		pod: func(pod v1.Pod) []string {
+			var out []string
+			for _, volume := range pod.Spec.Volumes {
+				source := volume.VolumeSource
+				if source.ConfigMap != nil || source.CSI != nil || source.DownwardAPI != nil || source.EmptyDir != nil ||
+					source.Ephemeral != nil || source.PersistentVolumeClaim != nil || source.Projected != nil || source.Secret != nil {
+					continue
+				}
+				if source.HostPath != nil {
+					// already reported by the baseline check
+					continue
+				}
+				out = append(out, "volume "+volume.Name)
+			}
+			return out
+		},
+	},
+	{
+		name:  "privilegeEscalation",
+		level: pssRestricted,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			csc := container.SecurityContext
+			if csc == nil || csc.AllowPrivilegeEscalation == nil || *csc.AllowPrivilegeEscalation {
+				return []string{"allowPrivilegeEscalation!=false"}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "runAsNonRoot",
+		level: pssRestricted,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			var nonRoot *bool
+
+			if pod.Spec.SecurityContext != nil {
+				nonRoot = pod.Spec.SecurityContext.RunAsNonRoot
+			}
+			if container.SecurityContext != nil && container.SecurityContext.RunAsNonRoot != nil {
+				nonRoot = container.SecurityContext.RunAsNonRoot
+			}
+
+			if nonRoot == nil || !*nonRoot {
+				return []string{"runAsNonRoot!=true"}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "runAsUser",
+		level: pssRestricted,
+		pod: func(pod v1.Pod) []string {
+			psc := pod.Spec.SecurityContext
+			if psc != nil && psc.RunAsUser != nil && *psc.RunAsUser == 0 {
+				return []string{"pod runAsUser=0"}
+			}
+			return nil
+		},
+		container: func(pod v1.Pod, container v1.Container) []string {
+			csc := container.SecurityContext
+			if csc != nil && csc.RunAsUser != nil && *csc.RunAsUser == 0 {
+				return []string{"runAsUser=0"}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "seccompProfile",
+		level: pssRestricted,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			var profile *v1.SeccompProfile
+
+			if pod.Spec.SecurityContext != nil {
+				profile = pod.Spec.SecurityContext.SeccompProfile
+			}
+			if container.SecurityContext != nil && container.SecurityContext.SeccompProfile != nil {
+				profile = container.SecurityContext.SeccompProfile
+			}
+
+			if profile == nil {
+				return []string{"seccompProfile not set"}
+			}
+			if profile.Type != v1.SeccompProfileTypeRuntimeDefault && profile.Type != v1.SeccompProfileTypeLocalhost {
+				return []string{"seccompProfile=" + string(profile.Type)}
+			}
+			return nil
+		},
+	},
+	{
+		name:  "capabilitiesDrop",
+		level: pssRestricted,
+		container: func(pod v1.Pod, container v1.Container) []string {
+			var out []string
+			dropAll := false
+
+			csc := container.SecurityContext
+			if csc != nil && csc.Capabilities != nil {
+				for _, capability := range csc.Capabilities.Drop {
+					if capability == "ALL" {
+						dropAll = true
+					}
+				}
+				for _, capability := range csc.Capabilities.Add {
+					if capability != "NET_BIND_SERVICE" && pssBaselineCapabilities[capability] {
+						out = append(out, "capabilities add="+string(capability))
+					}
+				}
+			}
+
+			if !dropAll {
+				out = append([]string{"capabilities drop ALL missing"}, out...)
+			}
+			return out
+		},
+	},
+}
+
+// pssCheckSELinux checks the SELinux type is allowed and the user and role are not set
+func pssCheckSELinux(options *v1.SELinuxOptions) []string {
+	var out []string
+
+	if options == nil {
+		return nil
+	}
+
+	if !pssSELinuxTypes[options.Type] {
+		out = append(out, "seLinux type="+options.Type)
+	}
+	if len(options.User) > 0 {
+		out = append(out, "seLinux user="+options.User)
+	}
+	if len(options.Role) > 0 {
+		out = append(out, "seLinux role="+options.Role)
+	}
+
+	return out
+}
+
+// pssEvaluate runs all pod and container checks returning each failure
+func pssEvaluate(pod v1.Pod, container v1.Container) []checkResult {
+	var results []checkResult
+
+	for _, check := range pssChecks {
+		var messages []string
+
+		if check.pod != nil {
+			messages = append(messages, check.pod(pod)...)
+		}
+		if check.container != nil {
+			messages = append(messages, check.container(pod, container)...)
+		}
+
+		for _, message := range messages {
+			results = append(results, checkResult{
+				name:    check.name,
+				level:   check.level,
+				message: message,
+			})
+		}
+	}
+
+	return results
+}
+
+// pssLevel returns the most secure level that has no failures
+func pssLevel(results []checkResult) string {
+	level := pssRestricted
+
+	for _, result := range results {
+		if result.level == pssBaseline {
+			return pssPrivileged
+		}
+		if result.level == pssRestricted {
+			level = pssBaseline
+		}
+	}
+
+	return level
+}
+
+// pssLevelIndex returns the position of the level in pssLevels, -1 is returned for unknown levels
+func pssLevelIndex(level string) int {
+	for i, name := range pssLevels {
+		if name == level {
+			return i
+		}
+	}
+	return -1
+}
+
+func PSS(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+	log := logger{location: "PSS"}
+	log.Debug("Start")
+
+	loopinfo := pss{}
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	if cmd.Flag("level") != nil {
+		level := cmd.Flag("level").Value.String()
+		if len(level) > 0 {
+			if pssLevelIndex(level) < 0 {
+				return errors.New("error: level must be one of privileged, baseline or restricted")
+			}
+			loopinfo.FailLevel = level
+		}
+	}
+
+	table := Table{}
+	builder.Table = &table
+
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	if commonFlagList.outputAs == "junit" {
+		return printJUnit("pss", loopinfo.Checked)
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type pss struct {
+	FailLevel string
+	Checked   []checkedContainer
+}
+
+func (s *pss) Headers() []string {
+	return []string{
+		"LEVEL", "VIOLATIONS",
+	}
+}
+
+func (s *pss) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *pss) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *pss) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	var violations []string
+
+	if len(rows) == 0 {
+		return make([]Cell, 2), nil
+	}
+
+	// the branch can only be as secure as its least secure container
+	level := pssRestricted
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		if pssLevelIndex(r[0].text) < pssLevelIndex(level) {
+			level = r[0].text
+		}
+		for _, violation := range strings.Split(r[1].text, ", ") {
+			if len(violation) > 0 && !seen[violation] {
+				seen[violation] = true
+				violations = append(violations, violation)
+			}
+		}
+	}
+	sort.Strings(violations)
+
+	return []Cell{
+		NewCellText(level),
+		NewCellText(strings.Join(violations, ", ")),
+	}, nil
+}
+
+func (s *pss) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return s.pssBuildRow(container, info), nil
+}
+
+func (s *pss) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return s.pssBuildRow(v1.Container(container.EphemeralContainerCommon), info), nil
+}
+
+// saveChecked keeps the results that fail the chosen level, restricted is used when no level was chosen
+func (s *pss) saveChecked(pod v1.Pod, containerName string, results []checkResult) {
+	target := s.FailLevel
+	if len(target) == 0 {
+		target = pssRestricted
+	}
+
+	checked := checkedContainer{
+		namespace:     pod.Namespace,
+		podName:       pod.Name,
+		containerName: containerName,
+	}
+
+	for _, result := range results {
+		// baseline checks also fail the restricted level
+		if pssLevelIndex(result.level) <= pssLevelIndex(target) {
+			checked.results = append(checked.results, result)
+		}
+	}
+
+	s.Checked = append(s.Checked, checked)
+}
+
+func (s *pss) pssBuildRow(container v1.Container, info BuilderInformation) [][]Cell {
+	var violations []string
+
+	results := pssEvaluate(info.Data.pod, container)
+	level := pssLevel(results)
+	s.saveChecked(info.Data.pod, container.Name, results)
+
+	if len(s.FailLevel) > 0 && pssLevelIndex(level) >= pssLevelIndex(s.FailLevel) {
+		// container meets the requested level so we dont show it
+		return [][]Cell{}
+	}
+
+	for _, result := range results {
+		violations = append(violations, result.message)
+	}
+
+	return [][]Cell{{
+		NewCellText(level),
+		NewCellText(strings.Join(violations, ", ")),
+	}}
+}
@@ -0,0 +1,65 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
+// ********
+// pssLevel
+// ********
+type pssLevelTest struct {
+	arg1     v1.Pod
+	expected string
+}
+
+var restrictedContext = &v1.SecurityContext{
+	AllowPrivilegeEscalation: boolPtr(false),
+	RunAsNonRoot:             boolPtr(true),
+	SeccompProfile:           &v1.SeccompProfile{Type: v1.SeccompProfileTypeRuntimeDefault},
+	Capabilities:             &v1.Capabilities{Drop: []v1.Capability{"ALL"}, Add: []v1.Capability{"NET_BIND_SERVICE"}},
+}
+
+var pssLevelTests = []pssLevelTest{
+	// nothing set only meets baseline
+	{v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app"}}}}, pssBaseline},
+	// fully locked down container
+	{v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", SecurityContext: restrictedContext}}}}, pssRestricted},
+	// host namespaces are checked at the pod level
+	{v1.Pod{Spec: v1.PodSpec{HostNetwork: true, Containers: []v1.Container{{Name: "app", SecurityContext: restrictedContext}}}}, pssPrivileged},
+	// privileged containers
+	{v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", SecurityContext: &v1.SecurityContext{Privileged: boolPtr(true)}}}}}, pssPrivileged},
+	// capabilities outside of the baseline list
+	{v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", SecurityContext: &v1.SecurityContext{
+		Capabilities: &v1.Capabilities{Add: []v1.Capability{"SYS_ADMIN"}},
+	}}}}}, pssPrivileged},
+	// unsafe sysctl
+	{v1.Pod{Spec: v1.PodSpec{
+		SecurityContext: &v1.PodSecurityContext{Sysctls: []v1.Sysctl{{Name: "kernel.msgmax", Value: "1"}}},
+		Containers:      []v1.Container{{Name: "app", SecurityContext: restrictedContext}},
+	}}, pssPrivileged},
+	// unconfined apparmor profile
+	{v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+			"container.apparmor.security.beta.kubernetes.io/app": "unconfined",
+		}},
+		Spec: v1.PodSpec{Containers: []v1.Container{{Name: "app", SecurityContext: restrictedContext}}},
+	}, pssPrivileged},
+}
+
+func TestPSSLevel(t *testing.T) {
+
+	for i, test := range pssLevelTests {
+		results := pssEvaluate(test.arg1, test.arg1.Spec.Containers[0])
+		if output := pssLevel(results); output != test.expected {
+			t.Errorf("Test %d output %s not equal to expected %s (%v)", i, output, test.expected, results)
+		}
+	}
+
+}
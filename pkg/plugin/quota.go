@@ -0,0 +1,237 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var quotaShort = "Show how the ResourceQuotas of each namespace apply to the containers in a pod"
+
+var quotaDescription = ` Prints each ResourceQuota that applies to the pods namespace along with the containers own request or
+limit for every resource the quota tracks. The used and hard values of the quota are shown next to each
+container so you can see which containers are using up the quota, a - in the VALUE column means the
+container has not set a value that the quota requires and will fail admission.
+
+Quota scopes BestEffort, NotBestEffort, Terminating and NotTerminating are checked against each pod. Keys
+that count objects or PersistentVolumeClaim storage, such as requests.storage, persistentvolumeclaims and
+<class>.storageclass.storage.k8s.io/requests.storage, are not container values so they are not shown.`
+
+var quotaExample = `  # List quotas that apply to the containers in the current namespace
+  %[1]s quota
+
+  # List quotas that apply to the containers of a single pod
+  %[1]s quota my-pod-4jh36
+
+  # List quotas that apply to containers in all namespaces
+  %[1]s quota -A
+
+  # List quotas that have used more than 90 percent
+  %[1]s quota -m "%%USED>90"`
+
+func Quota(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Quota"}
+	log.Debug("Start")
+
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+
+	loopinfo := quota{}
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	loopinfo.Connection = &connect
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+
+	if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+		return errors.New("error: quotas can only be shown from a live cluster")
+	}
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+type quota struct {
+	Connection *Connector
+}
+
+func (s *quota) Headers() []string {
+	return []string{
+		"QUOTA", "RESOURCE", "VALUE", "USED", "HARD", "%USED",
+	}
+}
+
+func (s *quota) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *quota) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	// ephemeral containers are not counted by quotas
+	return [][]Cell{}, nil
+}
+
+func (s *quota) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *quota) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	// each row is for a different quota resource so there is nothing to add up
+	return make([]Cell, 6), nil
+}
+
+func (s *quota) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return s.quotaBuildRows(container.Resources, info)
+}
+
+func (s *quota) quotaBuildRows(res v1.ResourceRequirements, info BuilderInformation) ([][]Cell, error) {
+	var out [][]Cell
+
+	log := logger{location: "quota:quotaBuildRows"}
+	log.Debug("Start")
+
+	quotaList, err := s.Connection.GetResourceQuotas(info.Data.pod.Namespace)
+	if err != nil {
+		return [][]Cell{}, err
+	}
+
+	for _, rq := range quotaList {
+		if !quotaScopeMatches(rq, info.Data.pod) {
+			continue
+		}
+
+		hard := rq.Status.Hard
+		if len(hard) == 0 {
+			// quota controller hasnt caught up yet
+			hard = rq.Spec.Hard
+		}
+
+		keys := []string{}
+		for key := range hard {
+			keys = append(keys, string(key))
+		}
+		sort.Strings(keys)
+
+		for _, key := range keys {
+			var value Cell
+			var percentUsed Cell
+
+			resourceName, isLimit, ok := quotaResourceName(key)
+			if !ok {
+				continue
+			}
+
+			list := res.Requests
+			if isLimit {
+				list = res.Limits
+			}
+
+			if quantity, ok := list[resourceName]; ok {
+				value = NewCellText(quantity.String())
+			} else {
+				value = NewCellText("-")
+			}
+
+			hardQuantity := hard[v1.ResourceName(key)]
+			usedQuantity := rq.Status.Used[v1.ResourceName(key)]
+			if hardValue := hardQuantity.AsApproximateFloat64(); hardValue > 0 {
+				val := validateFloat64(usedQuantity.AsApproximateFloat64() / hardValue * 100)
+				percentUsed = NewCellFloat(fmt.Sprintf("%.2f", val), val)
+			}
+
+			out = append(out, []Cell{
+				NewCellText(rq.Name),
+				NewCellText(key),
+				value,
+				NewCellText(usedQuantity.String()),
+				NewCellText(hardQuantity.String()),
+				percentUsed,
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// quotaResourceName converts a quota key into the container resource name it limits, isLimit is true when
+//
+//	the quota applies to the containers limit rather than its request, ok is false for non container keys
+func quotaResourceName(key string) (resourceName v1.ResourceName, isLimit bool, ok bool) {
+	switch {
+	case key == "requests."+string(v1.ResourceStorage) || strings.Contains(key, ".storageclass.storage.k8s.io/"):
+		// storage is requested by PersistentVolumeClaims not containers
+		return "", false, false
+	case strings.HasPrefix(key, "requests."):
+		return v1.ResourceName(strings.TrimPrefix(key, "requests.")), false, true
+	case strings.HasPrefix(key, "limits."):
+		return v1.ResourceName(strings.TrimPrefix(key, "limits.")), true, true
+	case key == "cpu" || key == "memory" || key == "ephemeral-storage":
+		// the short names are the same as requests.
+		return v1.ResourceName(key), false, true
+	case strings.HasPrefix(key, v1.ResourceHugePagesPrefix):
+		return v1.ResourceName(key), false, true
+	}
+
+	return "", false, false
+}
+
+// quotaScopeMatches checks the quotas scopes against the pod, scopes that cant be checked are treated as a match
+func quotaScopeMatches(rq v1.ResourceQuota, pod v1.Pod) bool {
+	for _, scope := range rq.Spec.Scopes {
+		switch scope {
+		case v1.ResourceQuotaScopeBestEffort:
+			if podQOSClass(pod) != v1.PodQOSBestEffort {
+				return false
+			}
+		case v1.ResourceQuotaScopeNotBestEffort:
+			if podQOSClass(pod) == v1.PodQOSBestEffort {
+				return false
+			}
+		case v1.ResourceQuotaScopeTerminating:
+			if pod.Spec.ActiveDeadlineSeconds == nil {
+				return false
+			}
+		case v1.ResourceQuotaScopeNotTerminating:
+			if pod.Spec.ActiveDeadlineSeconds != nil {
+				return false
+			}
+		}
+	}
+
+	return true
+}
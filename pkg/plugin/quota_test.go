@@ -0,0 +1,45 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// *****************
+// quotaResourceName
+// *****************
+type quotaResourceNameTest struct {
+	arg1            string
+	expectedName    v1.ResourceName
+	expectedIsLimit bool
+	expectedOk      bool
+}
+
+var quotaResourceNameTests = []quotaResourceNameTest{
+	{"requests.cpu", v1.ResourceCPU, false, true},
+	{"limits.memory", v1.ResourceMemory, true, true},
+	{"cpu", v1.ResourceCPU, false, true},
+	{"ephemeral-storage", v1.ResourceEphemeralStorage, false, true},
+	{"requests.ephemeral-storage", v1.ResourceEphemeralStorage, false, true},
+	{"hugepages-2Mi", "hugepages-2Mi", false, true},
+	{"requests.nvidia.com/gpu", "nvidia.com/gpu", false, true},
+	// storage and object counts are not set on containers
+	{"requests.storage", "", false, false},
+	{"gold.storageclass.storage.k8s.io/requests.storage", "", false, false},
+	{"gold.storageclass.storage.k8s.io/persistentvolumeclaims", "", false, false},
+	{"persistentvolumeclaims", "", false, false},
+	{"pods", "", false, false},
+	{"count/deployments.apps", "", false, false},
+}
+
+func TestQuotaResourceName(t *testing.T) {
+
+	for _, test := range quotaResourceNameTests {
+		name, isLimit, ok := quotaResourceName(test.arg1)
+		if name != test.expectedName || isLimit != test.expectedIsLimit || ok != test.expectedOk {
+			t.Errorf("Output %s %t %t not equal to expected %s %t %t for %s", name, isLimit, ok, test.expectedName, test.expectedIsLimit, test.expectedOk, test.arg1)
+		}
+	}
+
+}
@@ -0,0 +1,418 @@
+package plugin
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+var rbacShort = "Show the ServiceAccount of each pod and the permissions it has been granted"
+
+var rbacDescription = ` Prints the ServiceAccount used by each pod, whether its token is automounted and the Roles and
+ClusterRoles bound to the ServiceAccount through RoleBindings and ClusterRoleBindings. Bindings to the
+system:serviceaccounts and system:authenticated groups are included as they apply to every ServiceAccount token.
+
+POD_AUTOMOUNT and SA_AUTOMOUNT show the automountServiceAccountToken setting of the pod and the ServiceAccount,
+AUTOMOUNT shows the result, the pod setting is used first then the ServiceAccount, the token is mounted when
+neither are set.
+
+HIGH_RISK lists any of the following permissions the ServiceAccount has:
+  wildcard-verbs     a rule allows all verbs
+  wildcard-groups    a rule applies to all api groups
+  secrets-read       secrets can be read with get, list or watch
+  pods-exec          commands can be run in pods using pods/exec
+  workload-create    pods or workloads such as deployments and jobs can be created
+
+When reading from a file any ServiceAccounts, Roles, ClusterRoles and bindings in the file are used, objects
+without a namespace are treated as being in the default namespace.`
+
+var rbacExample = `  # List the ServiceAccount and roles of pods in the current namespace
+  %[1]s rbac
+
+  # List each rule granted to the ServiceAccount of a single pod
+  %[1]s rbac my-pod-4jh36 --verbose
+
+  # List pods in all namespaces that have high risk permissions
+  %[1]s rbac -A -m "HIGH_RISK!="
+
+  # Check the permissions of pods using the ServiceAccounts and roles from a file
+  %[1]s rbac -f manifests.yaml`
+
+func RBAC(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "RBAC"}
+	log.Debug("Start")
+
+	loopinfo := rbac{}
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.ShowInitContainers = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	connect.rbacSnapshot = len(commonFlagList.inputFilename) > 0 || stdinChanged
+
+	loopinfo.Connection = &connect
+
+	if cmd.Flag("verbose").Value.String() == "true" {
+		loopinfo.ShowRules = true
+	}
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+// boundRole is a Role or ClusterRole along with the rules it grants
+type boundRole struct {
+	name  string
+	rules []rbacv1.PolicyRule
+}
+
+type rbac struct {
+	Connection *Connector
+	ShowRules  bool
+}
+
+func (s *rbac) Headers() []string {
+	return []string{
+		"SERVICEACCOUNT", "POD_AUTOMOUNT", "SA_AUTOMOUNT", "AUTOMOUNT", "ROLES", "HIGH_RISK",
+		"ROLE", "API_GROUPS", "RESOURCES", "VERBS",
+	}
+}
+
+func (s *rbac) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *rbac) HideColumns(info BuilderInformation) []int {
+	if !s.ShowRules {
+		return []int{6, 7, 8, 9}
+	}
+	return []int{}
+}
+
+func (s *rbac) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, 10)
+
+	if info.TypeName == TypeNamePod {
+		// every container in the pod shares the same ServiceAccount
+		rows, err := s.rbacBuildRows(info, false)
+		if err != nil {
+			return rowOut, err
+		}
+		copy(rowOut, rows[0])
+	}
+
+	return rowOut, nil
+}
+
+func (s *rbac) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return s.rbacBuildRows(info, s.ShowRules)
+}
+
+func (s *rbac) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return s.rbacBuildRows(info, s.ShowRules)
+}
+
+func (s *rbac) rbacBuildRows(info BuilderInformation, showRules bool) ([][]Cell, error) {
+	var out [][]Cell
+	var podAutomount, saAutomount Cell
+	var roleNames []string
+
+	log := logger{location: "rbac:rbacBuildRows"}
+	log.Debug("Start")
+
+	pod := info.Data.pod
+	namespace := pod.Namespace
+	if len(namespace) == 0 {
+		namespace = "default"
+	}
+
+	accountName := pod.Spec.ServiceAccountName
+	if len(accountName) == 0 {
+		accountName = "default"
+	}
+
+	automount := true
+	if pod.Spec.AutomountServiceAccountToken != nil {
+		automount = *pod.Spec.AutomountServiceAccountToken
+		podAutomount = NewCellText(fmt.Sprintf("%t", automount))
+	}
+
+	sa, err := s.Connection.GetServiceAccount(accountName, namespace)
+	if err != nil {
+		return [][]Cell{}, err
+	}
+	if sa != nil && sa.AutomountServiceAccountToken != nil {
+		saAutomount = NewCellText(fmt.Sprintf("%t", *sa.AutomountServiceAccountToken))
+		if pod.Spec.AutomountServiceAccountToken == nil {
+			automount = *sa.AutomountServiceAccountToken
+		}
+	}
+
+	roles, err := s.boundRoles(accountName, namespace)
+	if err != nil {
+		return [][]Cell{}, err
+	}
+
+	risks := []string{}
+	for _, role := range roles {
+		roleNames = append(roleNames, role.name)
+		for _, rule := range role.rules {
+			for _, risk := range ruleRisks(rule) {
+				risks = appendUnique(risks, risk)
+			}
+		}
+	}
+	sort.Strings(risks)
+
+	row := []Cell{
+		NewCellText(accountName),
+		podAutomount,
+		saAutomount,
+		NewCellText(fmt.Sprintf("%t", automount)),
+		NewCellText(strings.Join(roleNames, ",")),
+		NewCellText(strings.Join(risks, ",")),
+		Cell{},
+		Cell{},
+		Cell{},
+		Cell{},
+	}
+
+	if !showRules {
+		return [][]Cell{row}, nil
+	}
+
+	for _, role := range roles {
+		for _, rule := range role.rules {
+			ruleRow := make([]Cell, len(row))
+			copy(ruleRow, row)
+
+			groups := []string{}
+			for _, group := range rule.APIGroups {
+				if len(group) == 0 {
+					group = "core"
+				}
+				groups = append(groups, group)
+			}
+
+			resources := append([]string{}, rule.Resources...)
+			resources = append(resources, rule.NonResourceURLs...)
+
+			ruleRow[6] = NewCellText(role.name)
+			ruleRow[7] = NewCellText(strings.Join(groups, ","))
+			ruleRow[8] = NewCellText(strings.Join(resources, ","))
+			ruleRow[9] = NewCellText(strings.Join(rule.Verbs, ","))
+			out = append(out, ruleRow)
+		}
+	}
+
+	if len(out) == 0 {
+		out = append(out, row)
+	}
+
+	return out, nil
+}
+
+// boundRoles returns the Roles and ClusterRoles that are bound to the ServiceAccount, the role names are
+//
+//	prefixed with their kind
+func (s *rbac) boundRoles(accountName string, namespace string) ([]boundRole, error) {
+	var roles []boundRole
+
+	bindings, err := s.Connection.GetRoleBindings(namespace)
+	if err != nil {
+		return roles, err
+	}
+
+	for _, binding := range bindings {
+		if !subjectsMatch(binding.Subjects, accountName, namespace, binding.Namespace) {
+			continue
+		}
+
+		role, err := s.resolveRoleRef(binding.RoleRef, namespace)
+		if err != nil {
+			return roles, err
+		}
+		roles = append(roles, role)
+	}
+
+	clusterBindings, err := s.Connection.GetClusterRoleBindings()
+	if err != nil {
+		return roles, err
+	}
+
+	for _, binding := range clusterBindings {
+		if !subjectsMatch(binding.Subjects, accountName, namespace, "") {
+			continue
+		}
+
+		role, err := s.resolveRoleRef(binding.RoleRef, namespace)
+		if err != nil {
+			return roles, err
+		}
+		roles = append(roles, role)
+	}
+
+	return roles, nil
+}
+
+// resolveRoleRef looks up the rules of the Role or ClusterRole a binding points to, a missing role is returned
+//
+//	without any rules
+func (s *rbac) resolveRoleRef(ref rbacv1.RoleRef, namespace string) (boundRole, error) {
+	role := boundRole{name: ref.Kind + "/" + ref.Name}
+
+	switch ref.Kind {
+	case "Role":
+		r, err := s.Connection.GetRole(ref.Name, namespace)
+		if err != nil {
+			return role, err
+		}
+		if r != nil {
+			role.rules = r.Rules
+		}
+
+	case "ClusterRole":
+		r, err := s.Connection.GetClusterRole(ref.Name)
+		if err != nil {
+			return role, err
+		}
+		if r != nil {
+			role.rules = r.Rules
+		}
+	}
+
+	return role, nil
+}
+
+// subjectsMatch checks if any subject is the ServiceAccount, one of the system:serviceaccounts groups it
+//
+//	belongs to or system:authenticated, bindingNamespace is used when a ServiceAccount subject has no namespace set
+func subjectsMatch(subjects []rbacv1.Subject, accountName string, namespace string, bindingNamespace string) bool {
+	for _, subject := range subjects {
+		switch subject.Kind {
+		case rbacv1.ServiceAccountKind:
+			subjectNamespace := subject.Namespace
+			if len(subjectNamespace) == 0 {
+				subjectNamespace = bindingNamespace
+			}
+			if subject.Name == accountName && subjectNamespace == namespace {
+				return true
+			}
+
+		case rbacv1.GroupKind:
+			switch subject.Name {
+			case "system:serviceaccounts", "system:serviceaccounts:" + namespace, "system:authenticated":
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// ruleRisks returns the names of any high risk permissions granted by the rule
+func ruleRisks(rule rbacv1.PolicyRule) []string {
+	var risks []string
+
+	hasVerb := func(verbs ...string) bool {
+		for _, v := range rule.Verbs {
+			if v == rbacv1.VerbAll {
+				return true
+			}
+			for _, verb := range verbs {
+				if v == verb {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	hasResource := func(resources ...string) bool {
+		for _, r := range rule.Resources {
+			if r == rbacv1.ResourceAll {
+				return true
+			}
+			for _, resource := range resources {
+				if r == resource {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	// the core api group is an empty string
+	hasGroup := func(group string) bool {
+		for _, g := range rule.APIGroups {
+			if g == rbacv1.APIGroupAll || g == group {
+				return true
+			}
+		}
+		return false
+	}
+
+	if hasVerb() {
+		risks = append(risks, "wildcard-verbs")
+	}
+
+	for _, g := range rule.APIGroups {
+		if g == rbacv1.APIGroupAll {
+			risks = append(risks, "wildcard-groups")
+			break
+		}
+	}
+
+	if hasGroup("") && hasResource("secrets") && hasVerb("get", "list", "watch") {
+		risks = append(risks, "secrets-read")
+	}
+
+	if hasGroup("") && hasResource("pods/exec", "pods/*") && hasVerb("create", "get") {
+		risks = append(risks, "pods-exec")
+	}
+
+	workloads := (hasGroup("") && hasResource("pods")) ||
+		(hasGroup("apps") && hasResource("deployments", "replicasets", "statefulsets", "daemonsets")) ||
+		(hasGroup("batch") && hasResource("jobs", "cronjobs"))
+	if workloads && hasVerb("create") {
+		risks = append(risks, "workload-create")
+	}
+
+	return risks
+}
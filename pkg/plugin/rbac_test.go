@@ -0,0 +1,73 @@
+package plugin
+
+import (
+	"reflect"
+	"testing"
+
+	rbacv1 "k8s.io/api/rbac/v1"
+)
+
+// *********
+// ruleRisks
+// *********
+type ruleRisksTest struct {
+	arg1     rbacv1.PolicyRule
+	expected []string
+}
+
+var ruleRisksTests = []ruleRisksTest{
+	{rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"configmaps"}, Verbs: []string{"get"}}, nil},
+	{rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"secrets"}, Verbs: []string{"list"}}, []string{"secrets-read"}},
+	// secrets in another api group are not the core secrets
+	{rbacv1.PolicyRule{APIGroups: []string{"example.com"}, Resources: []string{"secrets"}, Verbs: []string{"get"}}, nil},
+	{rbacv1.PolicyRule{APIGroups: []string{"example.com"}, Resources: []string{"*"}, Verbs: []string{"create"}}, nil},
+	{rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"secrets"}, Verbs: []string{"get"}}, []string{"wildcard-groups", "secrets-read"}},
+	{rbacv1.PolicyRule{APIGroups: []string{""}, Resources: []string{"pods/exec"}, Verbs: []string{"create"}}, []string{"pods-exec"}},
+	{rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"deployments"}, Verbs: []string{"create"}}, []string{"workload-create"}},
+	{rbacv1.PolicyRule{APIGroups: []string{"apps"}, Resources: []string{"jobs"}, Verbs: []string{"create"}}, nil},
+	{rbacv1.PolicyRule{APIGroups: []string{"batch"}, Resources: []string{"cronjobs"}, Verbs: []string{"create"}}, []string{"workload-create"}},
+	{rbacv1.PolicyRule{APIGroups: []string{"*"}, Resources: []string{"*"}, Verbs: []string{"*"}},
+		[]string{"wildcard-verbs", "wildcard-groups", "secrets-read", "pods-exec", "workload-create"}},
+}
+
+func TestRuleRisks(t *testing.T) {
+
+	for _, test := range ruleRisksTests {
+		if output := ruleRisks(test.arg1); !reflect.DeepEqual(output, test.expected) {
+			t.Errorf("Output %v not equal to expected %v", output, test.expected)
+		}
+	}
+
+}
+
+// *************
+// subjectsMatch
+// *************
+type subjectsMatchTest struct {
+	arg1     rbacv1.Subject
+	expected bool
+}
+
+var subjectsMatchTests = []subjectsMatchTest{
+	{rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "app", Namespace: "web"}, true},
+	// the binding namespace is used when the subject has none
+	{rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "app"}, true},
+	{rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "app", Namespace: "other"}, false},
+	{rbacv1.Subject{Kind: rbacv1.ServiceAccountKind, Name: "default", Namespace: "web"}, false},
+	{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts"}, true},
+	{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:web"}, true},
+	{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:serviceaccounts:other"}, false},
+	{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:authenticated"}, true},
+	{rbacv1.Subject{Kind: rbacv1.GroupKind, Name: "system:unauthenticated"}, false},
+	{rbacv1.Subject{Kind: rbacv1.UserKind, Name: "app"}, false},
+}
+
+func TestSubjectsMatch(t *testing.T) {
+
+	for _, test := range subjectsMatchTests {
+		if output := subjectsMatch([]rbacv1.Subject{test.arg1}, "app", "web", "web"); output != test.expected {
+			t.Errorf("Output %t not equal to expected %t for %s %s", output, test.expected, test.arg1.Kind, test.arg1.Name)
+		}
+	}
+
+}
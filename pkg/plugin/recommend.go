@@ -0,0 +1,556 @@
+package plugin
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+	v1beta1 "k8s.io/metrics/pkg/apis/metrics/v1beta1"
+	"sigs.k8s.io/yaml"
+)
+
+var recommendShort = "Suggest cpu or memory requests and limits based on the observed usage of each container"
+
+var recommendDescription = ` Compares the observed cpu or memory usage of each container with its configured requests and
+limits and suggests new values. Usage can be read once or sampled multiple times using the
+--samples and --interval flags, the suggested request is calculated from the selected percentile
+of the samples plus headroom and the suggested limit from the highest sample plus headroom. All
+suggestions are rounded up to the nearest --round value. Limits are only suggested when the
+container already has a limit set.
+
+The STATUS column shows over when the current request is more than one rounding step above the
+suggestion and under when it is below the suggestion. Using -o patch prints a strategic merge
+patch document for each Deployment, StatefulSet and DaemonSet that owns the selected pods, the comment
+above each patch names the workload it applies to. A patch can be saved to a file and applied using
+kubectl patch deployment my-deployment --patch-file my-patch.yaml, ephemeral containers are left out as they
+cant be set from the workload template.
+
+Requires the metrics server, recommendations can not be made when reading from a file.`
+
+var recommendExample = `  # Suggest cpu requests and limits for containers in the current namespace
+  %[1]s recommend cpu
+
+  # Suggest memory requests and limits for a single pod
+  %[1]s recommend memory my-pod-4jh36
+
+  # Take 10 samples 30 seconds apart and use the 90th percentile plus 30%% headroom
+  %[1]s recommend cpu --samples 10 --interval 30s --percentile 90 --headroom 30
+
+  # Round memory suggestions up to the nearest 128Mi
+  %[1]s recommend memory --round 128Mi
+
+  # Show only the containers that are over provisioned
+  %[1]s recommend memory --match 'STATUS=over'
+
+  # Print strategic merge patches for the owning deployments
+  %[1]s recommend cpu -l app=web -o patch`
+
+// Recommend compares the usage of each container with its configured resources and suggests new values
+func Recommend(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+	var podNames []string
+
+	log := logger{location: "Recommend"}
+	log.Debug("Start")
+
+	if len(args) == 0 {
+		return errors.New("error: a resource type is required, either cpu or memory")
+	}
+
+	resourceType := strings.ToLower(args[0])
+	switch resourceType {
+	case "cpu":
+	case "mem", "memory":
+		resourceType = "memory"
+	default:
+		return fmt.Errorf("error: unknown resource type \"%s\", only cpu and memory are supported", args[0])
+	}
+	podNames = args[1:]
+
+	builder := RowBuilder{}
+	builder.LoopSpec = true
+	builder.PodName = podNames
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+
+	loopinfo := recommend{}
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	loopinfo.ResourceType = resourceType
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+
+	if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+		return errors.New("error: recommendations need live metrics and can not be made when reading from a file")
+	}
+
+	if err := loopinfo.setOptions(cmd); err != nil {
+		return err
+	}
+
+	if err := connect.LoadMetricConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	for i := 0; i < loopinfo.SampleCount; i++ {
+		if i > 0 {
+			time.Sleep(loopinfo.Interval)
+		}
+		log.Debug("taking sample", i+1)
+		podStateList, err := connect.GetMetricPods(podNames)
+		if err != nil {
+			return err
+		}
+		loopinfo.addSamples(podStateList)
+	}
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	if commonFlagList.outputAs == "patch" {
+		return loopinfo.printPatches(connect.BuildOwnersList())
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+}
+
+type recommendation struct {
+	containerType string
+	request       int64
+	limit         int64
+}
+
+type recommend struct {
+	Samples      map[string]map[string][]v1.ResourceList // pod name -> container name -> samples
+	SampleCount  int
+	Interval     time.Duration
+	ResourceType string
+	BytesAs      string
+	Headroom     float64 // percentage added to the observed usage
+	Percentile   float64
+	RoundTo      int64 // millicores for cpu, bytes for memory
+
+	//           namespace  pod        container
+	results map[string]map[string]map[string]recommendation
+}
+
+// setOptions reads the recommend specific flags from the command line
+func (s *recommend) setOptions(cmd *cobra.Command) error {
+	var err error
+
+	s.SampleCount, err = cmd.Flags().GetInt("samples")
+	if err != nil {
+		return err
+	}
+	if s.SampleCount < 1 {
+		return errors.New("error: samples must be 1 or more")
+	}
+
+	s.Interval, err = cmd.Flags().GetDuration("interval")
+	if err != nil {
+		return err
+	}
+
+	headroom, err := cmd.Flags().GetInt("headroom")
+	if err != nil {
+		return err
+	}
+	if headroom < 0 {
+		return errors.New("error: headroom can not be negative")
+	}
+	s.Headroom = float64(headroom)
+
+	percentile, err := cmd.Flags().GetInt("percentile")
+	if err != nil {
+		return err
+	}
+	if percentile < 1 || percentile > 100 {
+		return errors.New("error: percentile must be between 1 and 100")
+	}
+	s.Percentile = float64(percentile)
+
+	roundTo := cmd.Flag("round").Value.String()
+	if len(roundTo) == 0 {
+		if s.ResourceType == "cpu" {
+			roundTo = "50m"
+		} else {
+			roundTo = "64Mi"
+		}
+	}
+	quantity, err := apires.ParseQuantity(roundTo)
+	if err != nil {
+		return fmt.Errorf("error: invalid round value \"%s\": %w", roundTo, err)
+	}
+	if s.ResourceType == "cpu" {
+		s.RoundTo = quantity.MilliValue()
+	} else {
+		s.RoundTo = quantity.Value()
+	}
+	if s.RoundTo <= 0 {
+		return errors.New("error: round value must be greater than 0")
+	}
+
+	if cmd.Flag("size") != nil {
+		s.BytesAs = cmd.Flag("size").Value.String()
+	}
+
+	return nil
+}
+
+// addSamples appends a single round of pod metrics to the list of samples
+func (s *recommend) addSamples(stateList []v1beta1.PodMetrics) {
+	if s.Samples == nil {
+		s.Samples = make(map[string]map[string][]v1.ResourceList)
+	}
+
+	for _, pod := range stateList {
+		if _, ok := s.Samples[pod.Name]; !ok {
+			s.Samples[pod.Name] = make(map[string][]v1.ResourceList)
+		}
+		for _, container := range pod.Containers {
+			s.Samples[pod.Name][container.Name] = append(s.Samples[pod.Name][container.Name], container.Usage)
+		}
+	}
+}
+
+func (s *recommend) Headers() []string {
+	return []string{
+		"SAMPLES", "USED", "MAX", "REQUEST", "LIMIT", "REC-REQUEST", "REC-LIMIT", "STATUS",
+	}
+}
+
+func (s *recommend) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *recommend) HideColumns(info BuilderInformation) []int {
+	// with a single sample USED and MAX are always the same
+	if s.SampleCount <= 1 {
+		return []int{0, 2}
+	}
+	return []int{}
+}
+
+func (s *recommend) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, 8)
+
+	// "SAMPLES", "USED", "MAX", "REQUEST", "LIMIT", "REC-REQUEST", "REC-LIMIT", "STATUS"
+	for _, r := range rows {
+		for i := 1; i <= 6; i++ {
+			rowOut[i].number += r[i].number
+		}
+	}
+
+	for i := 1; i <= 6; i++ {
+		rowOut[i].typ = 1
+		rowOut[i].text = s.formatValue(rowOut[i].number)
+	}
+
+	return rowOut, nil
+}
+
+func (s *recommend) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	out := make([][]Cell, 1)
+	out[0] = s.recommendBuildRow(container.Resources, info)
+	return out, nil
+}
+
+func (s *recommend) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	out := make([][]Cell, 1)
+	out[0] = s.recommendBuildRow(container.Resources, info)
+	return out, nil
+}
+
+func (s *recommend) recommendBuildRow(res v1.ResourceRequirements, info BuilderInformation) []Cell {
+	var used, peak, request, limit, recRequest, recLimit int64
+	var status string
+
+	log := logger{location: "recommend:recommendBuildRow"}
+	log.Debug("Start")
+
+	samples := s.sampleValues(s.Samples[info.PodName][info.Name])
+
+	if q, ok := res.Requests[v1.ResourceName(s.ResourceType)]; ok {
+		request = s.quantityValue(q)
+	}
+	if q, ok := res.Limits[v1.ResourceName(s.ResourceType)]; ok {
+		limit = s.quantityValue(q)
+	}
+
+	if len(samples) > 0 {
+		used = percentileValue(samples, s.Percentile)
+		peak = samples[len(samples)-1]
+
+		recRequest = roundUpTo(int64(float64(used)*(1+s.Headroom/100)), s.RoundTo)
+		if limit > 0 {
+			recLimit = roundUpTo(int64(float64(peak)*(1+s.Headroom/100)), s.RoundTo)
+			if recLimit < recRequest {
+				recLimit = recRequest
+			}
+		}
+
+		switch {
+		case request == 0:
+			status = "unset"
+		case request < recRequest:
+			status = "under"
+		case limit > 0 && limit < peak:
+			status = "under"
+		case request-recRequest >= s.RoundTo:
+			status = "over"
+		default:
+			status = "ok"
+		}
+
+		s.saveResult(info, recommendation{
+			containerType: info.ContainerType,
+			request:       recRequest,
+			limit:         recLimit,
+		})
+	}
+
+	cellList := []Cell{
+		NewCellInt(fmt.Sprintf("%d", len(samples)), int64(len(samples))),
+		s.valueCell(used, len(samples) > 0),
+		s.valueCell(peak, len(samples) > 0),
+		s.valueCell(request, request > 0),
+		s.valueCell(limit, limit > 0),
+		s.valueCell(recRequest, recRequest > 0),
+		s.valueCell(recLimit, recLimit > 0),
+		NewCellText(status),
+	}
+
+	log.Debug("cellList", cellList)
+	return cellList
+}
+
+// sampleValues converts the usage samples to millicores or bytes and returns them sorted smallest first
+func (s *recommend) sampleValues(samples []v1.ResourceList) []int64 {
+	values := []int64{}
+
+	for _, sample := range samples {
+		if q, ok := sample[v1.ResourceName(s.ResourceType)]; ok {
+			values = append(values, s.quantityValue(q))
+		}
+	}
+
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+	return values
+}
+
+// quantityValue returns the quantity as millicores for cpu and bytes for memory
+func (s *recommend) quantityValue(q apires.Quantity) int64 {
+	if s.ResourceType == "cpu" {
+		return q.MilliValue()
+	}
+	return q.Value()
+}
+
+// valueCell returns an int cell formatted for the current resource type, the text is left empty when show is false
+func (s *recommend) valueCell(value int64, show bool) Cell {
+	if !show {
+		return NewCellInt("", 0)
+	}
+	return NewCellInt(s.formatValue(value), value)
+}
+
+func (s *recommend) formatValue(value int64) string {
+	if s.ResourceType == "cpu" {
+		return fmt.Sprintf("%dm", value)
+	}
+	return memoryHumanReadable(value, s.BytesAs)
+}
+
+// quantityString returns the value as a string suitable for use in a pod spec
+func (s *recommend) quantityString(value int64) string {
+	if s.ResourceType == "cpu" {
+		return apires.NewMilliQuantity(value, apires.DecimalSI).String()
+	}
+	return apires.NewQuantity(value, apires.BinarySI).String()
+}
+
+func (s *recommend) saveResult(info BuilderInformation, rec recommendation) {
+	if s.results == nil {
+		s.results = make(map[string]map[string]map[string]recommendation)
+	}
+	if _, ok := s.results[info.Namespace]; !ok {
+		s.results[info.Namespace] = make(map[string]map[string]recommendation)
+	}
+	if _, ok := s.results[info.Namespace][info.PodName]; !ok {
+		s.results[info.Namespace][info.PodName] = make(map[string]recommendation)
+	}
+	s.results[info.Namespace][info.PodName][info.Name] = rec
+}
+
+// printPatches walks the owner tree and prints a strategic merge patch for each Deployment, StatefulSet
+// and DaemonSet, when a workload has multiple pods the largest suggestion for each container is used
+func (s *recommend) printPatches(ownerList []*LeafNode) error {
+	var walk func(nodes []*LeafNode) error
+
+	walk = func(nodes []*LeafNode) error {
+		for _, node := range nodes {
+			switch node.kind {
+			case TypeNameDeployment, TypeNameStatefulSet, TypeNameDaemonSet:
+				if err := s.printPatch(node); err != nil {
+					return err
+				}
+			default:
+				if err := walk(node.child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	return walk(ownerList)
+}
+
+func (s *recommend) printPatch(owner *LeafNode) error {
+	containers := make(map[string]recommendation)
+
+	for _, pod := range podsBelow(owner) {
+		for name, rec := range s.results[pod.Namespace][pod.Name] {
+			// ephemeral containers are added to running pods so they cant be set from the workload template
+			if rec.containerType == TypeIDEphemeralContainer {
+				continue
+			}
+			current, ok := containers[name]
+			if !ok {
+				containers[name] = rec
+				continue
+			}
+			if rec.request > current.request {
+				current.request = rec.request
+			}
+			if rec.limit > current.limit {
+				current.limit = rec.limit
+			}
+			containers[name] = current
+		}
+	}
+
+	if len(containers) == 0 {
+		return nil
+	}
+
+	names := []string{}
+	for name := range containers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	podSpec := make(map[string][]map[string]interface{})
+	for _, name := range names {
+		rec := containers[name]
+		resources := map[string]map[string]string{
+			"requests": {s.ResourceType: s.quantityString(rec.request)},
+		}
+		if rec.limit > 0 {
+			resources["limits"] = map[string]string{s.ResourceType: s.quantityString(rec.limit)}
+		}
+
+		listName := "containers"
+		if rec.containerType == TypeIDInitContainer {
+			listName = "initContainers"
+		}
+		podSpec[listName] = append(podSpec[listName], map[string]interface{}{
+			"name":      name,
+			"resources": resources,
+		})
+	}
+
+	patch := map[string]interface{}{
+		"spec": map[string]interface{}{
+			"template": map[string]interface{}{
+				"spec": podSpec,
+			},
+		},
+	}
+
+	patchYaml, err := yaml.Marshal(patch)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("---")
+	fmt.Printf("# %s %s/%s\n", owner.kind, owner.namespace, owner.name)
+	fmt.Print(string(patchYaml))
+
+	return nil
+}
+
+// podsBelow returns all the pods that are children of the given node
+func podsBelow(node *LeafNode) []v1.Pod {
+	podList := []v1.Pod{}
+
+	for _, child := range node.child {
+		if child.kind == TypeNamePod {
+			podList = append(podList, child.data.pod)
+			continue
+		}
+		podList = append(podList, podsBelow(child)...)
+	}
+
+	return podList
+}
+
+// percentileValue uses the nearest rank method to return the percentile from a sorted list
+func percentileValue(sorted []int64, percentile float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+
+	return sorted[rank-1]
+}
+
+// roundUpTo rounds value up to the next multiple of step, zero is rounded up to step
+func roundUpTo(value int64, step int64) int64 {
+	if step <= 0 {
+		return value
+	}
+	if value <= 0 {
+		return step
+	}
+	if value%step == 0 {
+		return value
+	}
+	return (value/step + 1) * step
+}
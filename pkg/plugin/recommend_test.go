@@ -0,0 +1,63 @@
+package plugin
+
+import "testing"
+
+// ***************
+// percentileValue
+// ***************
+type percentileValueTest struct {
+	sorted     []int64
+	percentile float64
+	expected   int64
+}
+
+var percentileValueTests = []percentileValueTest{
+	{[]int64{}, 90, 0},
+	{[]int64{5}, 1, 5},
+	{[]int64{5}, 100, 5},
+	{[]int64{10, 20, 30, 40}, 50, 20},
+	{[]int64{10, 20, 30, 40}, 51, 30},
+	{[]int64{10, 20, 30, 40}, 100, 40},
+	{[]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 90, 9},
+	{[]int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}, 1, 1},
+}
+
+func TestPercentileValue(t *testing.T) {
+
+	for _, test := range percentileValueTests {
+		if output := percentileValue(test.sorted, test.percentile); output != test.expected {
+			t.Errorf("percentile %v of %v: output %d not equal to expected %d", test.percentile, test.sorted, output, test.expected)
+		}
+	}
+
+}
+
+// *********
+// roundUpTo
+// *********
+type roundUpToTest struct {
+	value    int64
+	step     int64
+	expected int64
+}
+
+var roundUpToTests = []roundUpToTest{
+	{0, 50, 50},
+	{-10, 50, 50},
+	{1, 50, 50},
+	{50, 50, 50},
+	{51, 50, 100},
+	{149, 50, 150},
+	{70, 0, 70},
+	{70, -5, 70},
+}
+
+func TestRoundUpTo(t *testing.T) {
+
+	for _, test := range roundUpToTests {
+		if output := roundUpTo(test.value, test.step); output != test.expected {
+			t.Errorf("round %d to %d: output %d not equal to expected %d", test.value, test.step, output, test.expected)
+		}
+	}
+
+}
@@ -1,7 +1,9 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
@@ -56,6 +58,159 @@ func resourceExample(r string) string {
   %[1]s %[2]s -l "app in (web,mail)"`, "%[1]s", r)
 }
 
+var storageShort = "Show configured ephemeral-storage requests, limits and node allocatable of each container"
+
+var storageDescription = ` Prints the configured ephemeral-storage requests and limits of each container along with the
+allocatable ephemeral-storage of the node the pod is running on. If no name is specified the
+container storage details of all pods in the current namespace are shown.
+
+The T column in the table output denotes S for Standard and I for init containers`
+
+var storageExample = `  # List containers ephemeral-storage info from pods
+  %[1]s storage
+
+  # List container ephemeral-storage info from a single pod shown in gigabytes
+  %[1]s storage my-pod-4jh36 --size Gi
+
+  # List ephemeral-storage info for all containers named web-container searching all
+  # pods in the current namespace
+  %[1]s storage -c web-container
+
+  # List container ephemeral-storage info from all pods where label app matches web
+  %[1]s storage -l app=web`
+
+var resourceNameShort = "Show configured requests, limits and node allocatable of any named resource for each container"
+
+var resourceNameDescription = ` Prints the configured requests and limits of the named resource for each container along with
+the allocatable amount of the node the pod is running on. Useful for extended resources like
+nvidia.com/gpu or hugepages-2Mi, the first argument is always the resource name. If no pod name
+is specified the container details of all pods in the current namespace are shown.
+
+The T column in the table output denotes S for Standard and I for init containers`
+
+var resourceNameExample = `  # List containers gpu requests and limits
+  %[1]s resource nvidia.com/gpu
+
+  # List containers hugepages requests and limits from a single pod
+  %[1]s resource hugepages-2Mi my-pod-4jh36
+
+  # List gpu info for all containers named web-container searching all
+  # pods in the current namespace
+  %[1]s resource nvidia.com/gpu -c web-container
+
+  # List container gpu info from all pods where label app matches web
+  %[1]s resource nvidia.com/gpu -l app=web`
+
+// noLimitText is shown in the LIMIT column when a container has no limit set
+const noLimitText = "no limit"
+
+// unboundedResources are the resources a container can keep using when no limit is set, extended resources
+//
+//	and hugepages only ever get the amount that was asked for
+var unboundedResources = map[v1.ResourceName]bool{
+	v1.ResourceCPU:              true,
+	v1.ResourceMemory:           true,
+	v1.ResourceEphemeralStorage: true,
+}
+
+// resourceHandler converts the quantities of a single resource type into table cells
+type resourceHandler struct {
+	format     apires.Format                                         // default format used when a quantity is missing
+	quantity   func(s *resource, q *apires.Quantity) (string, int64) // request, limit and allocatable values
+	usage      func(s *resource, q *apires.Quantity) (string, int64) // metrics values, nil when metrics are not available
+	total      func(s *resource, value int64) string                 // formats summed request and limit values
+	usageTotal func(s *resource, value int64) string                 // formats summed metrics values
+}
+
+// resourceHandlers the list of resources that have their own formatting, any resource not listed
+//
+//	here is treated as a count, with the exception of hugepages which are shown as bytes
+var resourceHandlers = map[string]resourceHandler{
+	"cpu": {
+		format: apires.DecimalSI,
+		quantity: func(s *resource, q *apires.Quantity) (string, int64) {
+			if s.ShowRaw {
+				value := q.ScaledValue(apires.Nano)
+				return fmt.Sprintf("%dn", value), value
+			}
+			value := q.MilliValue()
+			return fmt.Sprintf("%dm", value), value
+		},
+		usage: func(s *resource, q *apires.Quantity) (string, int64) {
+			if s.ShowRaw {
+				// this returns nanocores as the display value when using --raw
+				return q.String(), q.ScaledValue(apires.Nano)
+			}
+			return fmt.Sprintf("%dm", q.MilliValue()), q.MilliValue()
+		},
+		total: func(s *resource, value int64) string {
+			return fmt.Sprintf("%dm", value)
+		},
+		usageTotal: func(s *resource, value int64) string {
+			if s.ShowRaw {
+				return fmt.Sprintf("%dn", value)
+			}
+			return fmt.Sprintf("%dm", value)
+		},
+	},
+	"memory": {
+		format:   apires.BinarySI,
+		quantity: quantityAsBytes,
+		usage: func(s *resource, q *apires.Quantity) (string, int64) {
+			// metrics are stored internally as kb
+			if s.ShowRaw {
+				return fmt.Sprintf("%dk", q.Value()), q.Value() / 1000
+			}
+			return memoryHumanReadable(q.Value(), s.BytesAs), q.Value() / 1000
+		},
+		total: totalAsBytes,
+		usageTotal: func(s *resource, value int64) string {
+			// everything is stored internally as kb so we need to * 1000 to get back to bytes
+			if s.ShowRaw {
+				return fmt.Sprintf("%dk", value)
+			}
+			return memoryHumanReadable(value*1000, s.BytesAs)
+		},
+	},
+	"ephemeral-storage": {
+		format:   apires.BinarySI,
+		quantity: quantityAsBytes,
+		total:    totalAsBytes,
+	},
+}
+
+// resourceCountHandler is used for extended resources like nvidia.com/gpu that are whole numbers
+var resourceCountHandler = resourceHandler{
+	format: apires.DecimalSI,
+	quantity: func(s *resource, q *apires.Quantity) (string, int64) {
+		return q.String(), q.Value()
+	},
+	total: func(s *resource, value int64) string {
+		return fmt.Sprintf("%d", value)
+	},
+}
+
+func quantityAsBytes(s *resource, q *apires.Quantity) (string, int64) {
+	return q.String(), q.Value()
+}
+
+func totalAsBytes(s *resource, value int64) string {
+	return memoryHumanReadable(value, s.BytesAs)
+}
+
+// getResourceHandler returns the handler used to format the named resource
+func getResourceHandler(resourceName string) resourceHandler {
+	if handler, ok := resourceHandlers[resourceName]; ok {
+		return handler
+	}
+
+	if strings.HasPrefix(resourceName, v1.ResourceHugePagesPrefix) {
+		return resourceHandlers["ephemeral-storage"]
+	}
+
+	return resourceCountHandler
+}
+
 func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string, resourceType string) error {
 
 	log := logger{location: "Resource"}
@@ -81,6 +236,8 @@ func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 	builder.SetFlagsFrom(commonFlagList)
 
 	loopinfo.ResourceType = resourceType
+	loopinfo.Connection = &connect
+	handler := getResourceHandler(resourceType)
 
 	stdinChanged, err := builder.HasStdinChanged()
 	if err != nil {
@@ -90,16 +247,34 @@ func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 	//only need to pull metrics info we are reading live data,
 	// if we read from a file metric data wont exist
 	if len(commonFlagList.inputFilename) == 0 && !stdinChanged {
-		if err := connect.LoadMetricConfig(kubeFlags); err != nil {
-			return err
-		}
-		podStateList, err := connect.GetMetricPods(args)
-		if err != nil {
-			log.Tell(err)
+		if handler.usage != nil {
+			if err := connect.LoadMetricConfig(kubeFlags); err != nil {
+				return err
+			}
+			podStateList, err := connect.GetMetricPods(args)
+			if err != nil {
+				log.Tell(err)
+			} else {
+				loopinfo.MetricsResource = loopinfo.podMetrics2Hashtable(podStateList)
+			}
 		} else {
-			loopinfo.MetricsResource = loopinfo.podMetrics2Hashtable(podStateList)
+			// resources without metrics show the nodes allocatable amount instead
+			loopinfo.ShowAllocatable = true
+		}
+
+		if commonFlagList.showNodeTree {
+			loopinfo.ShowAllocatable = true
 		}
 	}
+	loopinfo.ShowNodeTree = commonFlagList.showNodeTree
+
+	if cmd.Flag("defaults") != nil && cmd.Flag("defaults").Value.String() == "true" {
+		// the owners template is needed to see what was changed when the pod was admitted
+		if len(commonFlagList.inputFilename) > 0 || stdinChanged {
+			return errors.New("error: defaults can only be detected on a live cluster")
+		}
+		loopinfo.ShowDefaults = true
+	}
 
 	if cmd.Flag("size") != nil {
 		if len(cmd.Flag("size").Value.String()) > 0 {
@@ -137,18 +312,41 @@ func Resources(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, arg
 	return nil
 }
 
+// ResourceByName runs Resources using the first argument as the resource name
+func ResourceByName(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+	if len(args) == 0 {
+		return errors.New("error: a resource name is required, for example nvidia.com/gpu")
+	}
+
+	return Resources(cmd, kubeFlags, args[1:], args[0])
+}
+
 type resource struct {
+	Connection      *Connector
 	MetricsResource map[string]map[string]v1.ResourceList
 	ResourceType    string
 	BytesAs         string
 	ShowRaw         bool
 	ShowPrevious    bool
 	ShowDetails     bool
+	ShowAllocatable bool
+	ShowNodeTree    bool
+	ShowDefaults    bool
+	NodeTotals      map[string]nodeResources
+}
+
+// nodeResources holds the totals of all pods running on a single node
+type nodeResources struct {
+	used        int64
+	request     int64
+	limit       int64
+	allocatable int64
+	unlimited   bool // a pod on the node has no limit
 }
 
 func (s *resource) Headers() []string {
 	return []string{
-		"USED", "REQUEST", "LIMIT", "%REQ", "%LIMIT",
+		"USED", "REQUEST", "LIMIT", "%REQ", "%LIMIT", "ALLOCATABLE", "QOS", "%REQUESTED", "%OVERCOMMIT", "DEFAULTED",
 	}
 }
 
@@ -157,67 +355,144 @@ func (s *resource) BuildContainerStatus(container v1.ContainerStatus, info Build
 }
 
 func (s *resource) HideColumns(info BuilderInformation) []int {
-	return []int{}
+	var hideColumns []int
+
+	if getResourceHandler(s.ResourceType).usage == nil {
+		// no metrics means no used and % columns
+		hideColumns = append(hideColumns, 0, 3, 4)
+	}
+
+	if !s.ShowAllocatable {
+		hideColumns = append(hideColumns, 5)
+	}
+
+	if !s.ShowNodeTree {
+		// node totals are only calculated on the node rows
+		hideColumns = append(hideColumns, 7, 8)
+	}
+
+	if !s.ShowDefaults {
+		hideColumns = append(hideColumns, 9)
+	}
+
+	return hideColumns
 }
 
 func (s *resource) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	rowOut := make([]Cell, 5)
+	rowOut := make([]Cell, 10)
+	handler := getResourceHandler(s.ResourceType)
 
+	limited := true
 	for _, r := range rows {
 		// "USED", "REQUEST", "LIMIT", "%REQ", "%LIMIT",
 		rowOut[0].number += r[0].number
 		rowOut[1].number += r[1].number
 		rowOut[2].number += r[2].number
+		if r[2].text == noLimitText {
+			limited = false
+		}
 	}
 
-	floatfmt := "%.6f"
-	typefmt := "%d"
-	if s.ResourceType == "cpu" {
-		typefmt = "%dm"
+	if info.TypeName == TypeNamePod {
+		// pods are scheduled using the effective request not the sum of all containers
+		request, limit, podLimited := podEffectiveResources(info.Data.pod, v1.ResourceName(s.ResourceType))
+		_, rowOut[1].number = handler.quantity(s, &request)
+		_, rowOut[2].number = handler.quantity(s, &limit)
+		limited = podLimited
+
+		phase := info.Data.pod.Status.Phase
+		if s.ShowNodeTree && (phase == v1.PodSucceeded || phase == v1.PodFailed) {
+			// finished pods no longer hold any resources on the node
+			rowOut[0].number = 0
+			rowOut[1].number = 0
+			rowOut[2].number = 0
+			limited = true
+		}
 	}
+
+	if !limited {
+		// a single container without a limit means the total is unbounded
+		rowOut[2].number = 0
+	}
+
+	floatfmt := "%.6f"
 	if !s.ShowRaw {
 		floatfmt = "%.2f"
 	}
 
-	if s.ResourceType == "memory" {
-		// everything is stored internally as kb so we need to * 1000 to get back to bytes
-		if s.ShowRaw {
-			typefmt = "%dk"
-			rowOut[0].text = fmt.Sprintf(typefmt, rowOut[0].number)
-		} else {
-			rowOut[0].text = memoryHumanReadable(rowOut[0].number*1000, s.BytesAs)
-		}
-		rowOut[1].text = memoryHumanReadable(rowOut[1].number, s.BytesAs)
-		rowOut[2].text = memoryHumanReadable(rowOut[2].number, s.BytesAs)
-	} else {
-		if s.ShowRaw {
-			rowOut[0].text = fmt.Sprintf("%dn", rowOut[0].number)
-		} else {
-			rowOut[0].text = fmt.Sprintf(typefmt, rowOut[0].number)
-		}
-		rowOut[1].text = fmt.Sprintf(typefmt, rowOut[1].number)
-		rowOut[2].text = fmt.Sprintf(typefmt, rowOut[2].number)
+	if handler.usageTotal != nil {
+		rowOut[0].text = handler.usageTotal(s, rowOut[0].number)
+	}
+	rowOut[1].text = handler.total(s, rowOut[1].number)
+	rowOut[2].text = handler.total(s, rowOut[2].number)
+	if !limited {
+		rowOut[2].text = noLimitText
 	}
 
 	if rowOut[0].number > 0 {
 		if rowOut[1].number > 0.0 {
 			// calc % request
 			val := validateFloat64(float64(rowOut[0].number) / float64(rowOut[1].number) * 100)
-			rowOut[4].text = fmt.Sprintf(floatfmt, val)
-			rowOut[4].float = val
+			rowOut[3].text = fmt.Sprintf(floatfmt, val)
+			rowOut[3].float = val
 		}
 
 		if rowOut[2].number > 0.0 {
 			// calc % limit
 			val := validateFloat64(float64(rowOut[0].number) / float64(rowOut[2].number) * 100)
-			rowOut[3].text = fmt.Sprintf(floatfmt, val)
-			rowOut[3].float = val
+			rowOut[4].text = fmt.Sprintf(floatfmt, val)
+			rowOut[4].float = val
 		}
 	}
 
+	if info.TypeName == TypeNamePod {
+		rowOut[5] = s.allocatableCell(info.Data.pod.Spec.NodeName)
+
+		qos := string(podQOSClass(info.Data.pod))
+		if status := string(info.Data.pod.Status.QOSClass); len(status) > 0 && status != qos {
+			// the class calculated from the spec dosent match the one the cluster assigned
+			qos = fmt.Sprintf("%s (status %s)", qos, status)
+		}
+		rowOut[6] = NewCellText(qos)
+	}
+
+	if info.ContainerType == TypeIDNode && info.TypeName == TypeNameNode {
+		s.nodeBranch(info.Name, rowOut, floatfmt)
+	}
+
 	return rowOut, nil
 }
 
+// nodeBranch adds the allocatable amount to a node row and saves the node totals
+func (s *resource) nodeBranch(nodeName string, rowOut []Cell, floatfmt string) {
+	totals := nodeResources{
+		used:      rowOut[0].number,
+		request:   rowOut[1].number,
+		limit:     rowOut[2].number,
+		unlimited: rowOut[2].text == noLimitText,
+	}
+
+	rowOut[5] = s.allocatableCell(nodeName)
+	totals.allocatable = rowOut[5].number
+
+	if totals.allocatable > 0 {
+		// calc % of allocatable that has been requested
+		val := validateFloat64(float64(totals.request) / float64(totals.allocatable) * 100)
+		rowOut[7] = NewCellFloat(fmt.Sprintf(floatfmt, val), val)
+
+		if rowOut[2].text != noLimitText {
+			// calc % of allocatable the limits add up to
+			val = validateFloat64(float64(totals.limit) / float64(totals.allocatable) * 100)
+			rowOut[8] = NewCellFloat(fmt.Sprintf(floatfmt, val), val)
+		}
+	}
+
+	if s.NodeTotals == nil {
+		s.NodeTotals = make(map[string]nodeResources)
+	}
+	s.NodeTotals[nodeName] = totals
+}
+
 func (s *resource) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
 	metrics := s.MetricsResource[info.PodName][info.Name]
 	out := make([][]Cell, 1)
@@ -234,117 +509,56 @@ func (s *resource) BuildEphemeralContainerSpec(container v1.EphemeralContainer,
 
 func (s *resource) statsProcessTableRow(res v1.ResourceRequirements, metrics v1.ResourceList, info BuilderInformation, resource string) []Cell {
 	var cellList []Cell
-	var displayValue, request, limit, percentLimit, percentRequest string
-	var rawRequest, rawLimit, rawValue int64
+	var displayValue, percentLimit, percentRequest string
+	var rawValue int64
 	var rawPercentRequest, rawPercentLimit float64
 	var requestCell, limitCell Cell
 
 	log := logger{location: "resources:statsProcessTableRow"}
 	log.Debug("Start")
 
-	floatfmt := "%.6f"
+	handler := getResourceHandler(resource)
+	name := v1.ResourceName(resource)
 
-	if resource == "cpu" {
-		if res.Size() >= 3 {
-			if res.Limits.Cpu() != nil {
-				if s.ShowRaw {
-					rawLimit = res.Limits.Cpu().ScaledValue(apires.Nano)
-					limit = fmt.Sprintf("%dn", rawLimit)
-				} else {
-					rawLimit = res.Limits.Cpu().MilliValue()
-					limit = fmt.Sprintf("%dm", rawLimit)
-				}
-				limitCell = NewCellInt(limit, rawLimit)
-			}
+	floatfmt := "%.6f"
+	if !s.ShowRaw {
+		floatfmt = "%.2f"
+	}
 
-			if res.Requests.Cpu() != nil {
-				if s.ShowRaw {
-					rawRequest = res.Requests.Cpu().ScaledValue(apires.Nano)
-					request = fmt.Sprintf("%dn", rawRequest)
-				} else {
-					rawRequest = res.Requests.Cpu().MilliValue()
-					request = fmt.Sprintf("%dm", rawRequest)
-				}
-				requestCell = NewCellInt(request, rawRequest)
-			}
-		}
-		if metrics.Cpu() != nil {
-			if s.ShowRaw {
-				// this returns nanocores as the display value when using --raw
-				displayValue = metrics.Cpu().String()
-				rawValue = metrics.Cpu().ScaledValue(apires.Nano)
-			} else {
-				floatfmt = "%.2f"
-				displayValue = fmt.Sprintf("%dm", metrics.Cpu().MilliValue())
-				rawValue = metrics.Cpu().MilliValue()
-			}
+	limitQuantity := res.Limits.Name(name, handler.format)
+	requestQuantity := res.Requests.Name(name, handler.format)
 
-			if cpuVal := metrics.Cpu().AsApproximateFloat64(); cpuVal > 0 {
-				// check cpu limits has a value
-				if res.Limits.Cpu().AsApproximateFloat64() == 0 {
-					percentLimit = "-"
-					rawPercentLimit = 0.0
-				} else {
-					val := validateFloat64(cpuVal / res.Limits.Cpu().AsApproximateFloat64() * 100)
-					percentLimit = fmt.Sprintf(floatfmt, val)
-					rawPercentLimit = val
-				}
-				// check cpu requests has a value
-				if res.Requests.Cpu().AsApproximateFloat64() == 0 {
-					percentRequest = "-"
-					rawPercentRequest = 0.0
-				} else {
-					val := validateFloat64(cpuVal / res.Requests.Cpu().AsApproximateFloat64() * 100)
-					percentRequest = fmt.Sprintf(floatfmt, val)
-					rawPercentRequest = val
-				}
-			}
-		}
+	if res.Size() >= 3 {
+		limitCell = NewCellInt(handler.quantity(s, limitQuantity))
+		requestCell = NewCellInt(handler.quantity(s, requestQuantity))
+	}
 
+	if _, ok := res.Limits[name]; !ok && unboundedResources[name] {
+		limitCell = NewCellText(noLimitText)
 	}
 
-	if resource == "memory" {
-		if res.Size() >= 3 {
-			if res.Limits.Memory() != nil {
-				limit = res.Limits.Memory().String()
-				rawLimit = res.Limits.Memory().Value()
-				limitCell = NewCellInt(limit, rawLimit)
-			}
+	if handler.usage != nil {
+		usedQuantity := metrics.Name(name, handler.format)
+		displayValue, rawValue = handler.usage(s, usedQuantity)
 
-			if res.Requests.Memory() != nil {
-				request = res.Requests.Memory().String()
-				rawRequest = res.Requests.Memory().Value()
-				requestCell = NewCellInt(request, rawRequest)
-			}
-		}
-		if metrics.Memory() != nil {
-			rawValue = metrics.Memory().Value() / 1000
-			if s.ShowRaw {
-				displayValue = fmt.Sprintf("%dk", metrics.Memory().Value())
+		if usedVal := usedQuantity.AsApproximateFloat64(); usedVal > 0 {
+			// check limits has a value
+			if limitQuantity.AsApproximateFloat64() == 0 {
+				percentLimit = "-"
+				rawPercentLimit = 0.0
 			} else {
-				displayValue = memoryHumanReadable(metrics.Memory().Value(), s.BytesAs)
-				floatfmt = "%.2f"
+				val := validateFloat64(usedVal / limitQuantity.AsApproximateFloat64() * 100)
+				percentLimit = fmt.Sprintf(floatfmt, val)
+				rawPercentLimit = val
 			}
-
-			if memVal := metrics.Memory().AsApproximateFloat64(); memVal > 0 {
-				// check memory limits has a value
-				if res.Limits.Memory().AsApproximateFloat64() == 0 {
-					percentLimit = "-"
-					rawPercentLimit = 0.0
-				} else {
-					val := validateFloat64(memVal / res.Limits.Memory().AsApproximateFloat64() * 100)
-					percentLimit = fmt.Sprintf(floatfmt, val)
-					rawPercentLimit = val
-				}
-				// check memory requests has a value
-				if res.Requests.Memory().AsApproximateFloat64() == 0 {
-					percentRequest = "-"
-					rawPercentRequest = 0.0
-				} else {
-					val := validateFloat64(memVal / res.Requests.Memory().AsApproximateFloat64() * 100)
-					percentRequest = fmt.Sprintf(floatfmt, val)
-					rawPercentRequest = val
-				}
+			// check requests has a value
+			if requestQuantity.AsApproximateFloat64() == 0 {
+				percentRequest = "-"
+				rawPercentRequest = 0.0
+			} else {
+				val := validateFloat64(usedVal / requestQuantity.AsApproximateFloat64() * 100)
+				percentRequest = fmt.Sprintf(floatfmt, val)
+				rawPercentRequest = val
 			}
 		}
 	}
@@ -355,12 +569,90 @@ func (s *resource) statsProcessTableRow(res v1.ResourceRequirements, metrics v1.
 		limitCell,
 		NewCellFloat(percentRequest, rawPercentRequest),
 		NewCellFloat(percentLimit, rawPercentLimit),
+		s.allocatableCell(info.NodeName),
+		NewCellText(string(podQOSClass(info.Data.pod))),
+		Cell{},
+		Cell{},
+		s.defaultedCell(res, info),
 	)
 
 	log.Debug("cellList", cellList)
 	return cellList
 }
 
+// defaultedCell lists the request and limit values that are set on the container but not in the owners
+//
+//	pod template, these are normally added by a LimitRange when the pod was admitted
+func (s *resource) defaultedCell(res v1.ResourceRequirements, info BuilderInformation) Cell {
+	var containers []v1.Container
+	var defaulted []string
+
+	if !s.ShowDefaults {
+		return Cell{}
+	}
+
+	template := s.Connection.GetOwnerPodTemplate(info.Data.pod)
+	if template == nil {
+		// pods without an owner have nothing to compare against
+		return NewCellText("-")
+	}
+
+	switch info.ContainerType {
+	case TypeIDInitContainer:
+		containers = template.Spec.InitContainers
+	case TypeIDContainer:
+		containers = template.Spec.Containers
+	default:
+		return Cell{}
+	}
+
+	name := v1.ResourceName(s.ResourceType)
+	for _, container := range containers {
+		if container.Name != info.Name {
+			continue
+		}
+
+		if request, ok := res.Requests[name]; ok {
+			_, inTemplate := container.Resources.Requests[name]
+			// the api server copies a missing request from the limit, thats not a LimitRange default
+			limit, hasLimit := container.Resources.Limits[name]
+			if !inTemplate && !(hasLimit && limit.Cmp(request) == 0) {
+				defaulted = append(defaulted, "request")
+			}
+		}
+
+		if _, ok := res.Limits[name]; ok {
+			if _, inTemplate := container.Resources.Limits[name]; !inTemplate {
+				defaulted = append(defaulted, "limit")
+			}
+		}
+	}
+
+	return NewCellText(strings.Join(defaulted, ","))
+}
+
+// allocatableCell returns the allocatable amount of the current resource on the named node
+func (s *resource) allocatableCell(nodeName string) Cell {
+	log := logger{location: "resources:allocatableCell"}
+
+	if !s.ShowAllocatable || len(nodeName) == 0 {
+		return Cell{}
+	}
+
+	node, err := s.Connection.GetNode(nodeName)
+	if err != nil {
+		log.Debug(err)
+		return Cell{}
+	}
+
+	quantity, ok := node.Status.Allocatable[v1.ResourceName(s.ResourceType)]
+	if !ok {
+		return Cell{}
+	}
+
+	return NewCellInt(getResourceHandler(s.ResourceType).quantity(s, &quantity))
+}
+
 func (s *resource) podMetrics2Hashtable(stateList []v1beta1.PodMetrics) map[string]map[string]v1.ResourceList {
 	podState := make(map[string]map[string]v1.ResourceList)
 
@@ -372,3 +664,143 @@ func (s *resource) podMetrics2Hashtable(stateList []v1beta1.PodMetrics) map[stri
 	}
 	return podState
 }
+
+// podEffectiveResources returns the request and limit used when scheduling the pod, this is the larger
+//
+//	of the biggest init container or the sum of all app containers plus any pod overhead. Restartable init
+//	containers (sidecars) keep running so they are added to both. limited is false when any container has
+//	no cpu, memory or ephemeral-storage limit as the pod can then use an unbounded amount
+func podEffectiveResources(pod v1.Pod, name v1.ResourceName) (request apires.Quantity, limit apires.Quantity, limited bool) {
+	var sidecarRequest, sidecarLimit, initRequest, initLimit apires.Quantity
+
+	limited = true
+
+	for _, container := range pod.Spec.Containers {
+		if value, ok := container.Resources.Requests[name]; ok {
+			request.Add(value)
+		}
+		if value, ok := container.Resources.Limits[name]; ok {
+			limit.Add(value)
+		} else if unboundedResources[name] {
+			limited = false
+		}
+	}
+
+	// regular init containers run one at a time alongside any sidecars that started before them
+	for _, container := range pod.Spec.InitContainers {
+		value := container.Resources.Requests[name]
+		limitValue, ok := container.Resources.Limits[name]
+		if !ok && unboundedResources[name] {
+			limited = false
+		}
+
+		if isRestartableInitContainer(pod, container.Name) {
+			sidecarRequest.Add(value)
+			sidecarLimit.Add(limitValue)
+			initRequest = maxQuantity(initRequest, sidecarRequest)
+			initLimit = maxQuantity(initLimit, sidecarLimit)
+			continue
+		}
+
+		value.Add(sidecarRequest)
+		limitValue.Add(sidecarLimit)
+		initRequest = maxQuantity(initRequest, value)
+		initLimit = maxQuantity(initLimit, limitValue)
+	}
+
+	request.Add(sidecarRequest)
+	limit.Add(sidecarLimit)
+	request = maxQuantity(request, initRequest)
+	limit = maxQuantity(limit, initLimit)
+
+	if value, ok := pod.Spec.Overhead[name]; ok {
+		request.Add(value)
+		limit.Add(value)
+	}
+
+	if !limited {
+		limit = apires.Quantity{}
+	}
+
+	return request, limit, limited
+}
+
+// isRestartableInitContainer checks if an init container is a sidecar, the restartPolicy field is newer than
+//
+//	the api version we build with so sidecars are found by their status instead, a regular init container
+//	is never running once the pod has been initialized
+func isRestartableInitContainer(pod v1.Pod, containerName string) bool {
+	initialized := false
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == v1.PodInitialized && condition.Status == v1.ConditionTrue {
+			initialized = true
+		}
+	}
+	if !initialized {
+		return false
+	}
+
+	for _, status := range pod.Status.InitContainerStatuses {
+		if status.Name == containerName {
+			return status.State.Running != nil
+		}
+	}
+	return false
+}
+
+// maxQuantity returns the larger of the two quantities
+func maxQuantity(a apires.Quantity, b apires.Quantity) apires.Quantity {
+	if b.Cmp(a) > 0 {
+		return b.DeepCopy()
+	}
+	return a.DeepCopy()
+}
+
+// podQOSClass calculates the quality of service class from the pod spec using the same rules as
+//
+//	the kubelet, only cpu and memory are considered
+func podQOSClass(pod v1.Pod) v1.PodQOSClass {
+	isGuaranteed := true
+	isBestEffort := true
+
+	containers := append([]v1.Container{}, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	for _, container := range containers {
+		for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+			request, hasRequest := container.Resources.Requests[name]
+			limit, hasLimit := container.Resources.Limits[name]
+
+			if hasRequest && request.IsZero() {
+				hasRequest = false
+			}
+			if hasLimit && limit.IsZero() {
+				hasLimit = false
+			}
+
+			if hasRequest || hasLimit {
+				isBestEffort = false
+			}
+
+			if !hasLimit {
+				isGuaranteed = false
+				continue
+			}
+
+			// the api server defaults a missing request to the limit
+			if hasRequest && request.Cmp(limit) != 0 {
+				isGuaranteed = false
+			}
+		}
+	}
+
+	if isBestEffort {
+		return v1.PodQOSBestEffort
+	}
+
+	if isGuaranteed {
+		return v1.PodQOSGuaranteed
+	}
+
+	return v1.PodQOSBurstable
+}
@@ -0,0 +1,204 @@
+package plugin
+
+import (
+	"testing"
+
+	v1 "k8s.io/api/core/v1"
+	apires "k8s.io/apimachinery/pkg/api/resource"
+)
+
+func testContainer(request string, limit string) v1.Container {
+	container := v1.Container{
+		Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{},
+			Limits:   v1.ResourceList{},
+		},
+	}
+
+	if len(request) > 0 {
+		container.Resources.Requests[v1.ResourceCPU] = apires.MustParse(request)
+		container.Resources.Requests[v1.ResourceMemory] = apires.MustParse(request)
+	}
+	if len(limit) > 0 {
+		container.Resources.Limits[v1.ResourceCPU] = apires.MustParse(limit)
+		container.Resources.Limits[v1.ResourceMemory] = apires.MustParse(limit)
+	}
+
+	return container
+}
+
+// *********************
+// podEffectiveResources
+// *********************
+type podEffectiveResourcesTest struct {
+	arg1            v1.Pod
+	expectedRequest string
+	expectedLimit   string
+	expectedLimited bool
+}
+
+// testSidecarPod returns an initialized pod where the named init containers are still running
+func testSidecarPod(spec v1.PodSpec, running ...string) v1.Pod {
+	pod := v1.Pod{Spec: spec}
+	pod.Status.Conditions = []v1.PodCondition{{Type: v1.PodInitialized, Status: v1.ConditionTrue}}
+	for _, name := range running {
+		pod.Status.InitContainerStatuses = append(pod.Status.InitContainerStatuses, v1.ContainerStatus{
+			Name:  name,
+			State: v1.ContainerState{Running: &v1.ContainerStateRunning{}},
+		})
+	}
+	return pod
+}
+
+func testNamedContainer(name string, request string, limit string) v1.Container {
+	container := testContainer(request, limit)
+	container.Name = name
+	return container
+}
+
+var podEffectiveResourcesTests = []podEffectiveResourcesTest{
+	// no containers
+	{v1.Pod{}, "0", "0", true},
+	// app containers are added together
+	{v1.Pod{Spec: v1.PodSpec{
+		Containers: []v1.Container{testContainer("100m", "200m"), testContainer("50m", "300m")},
+	}}, "150m", "500m", true},
+	// a single container without a limit means the pod has no limit
+	{v1.Pod{Spec: v1.PodSpec{
+		Containers: []v1.Container{testContainer("100m", "200m"), testContainer("50m", "")},
+	}}, "150m", "0", false},
+	// init container is larger than all app containers
+	{v1.Pod{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{testContainer("500m", "1")},
+		Containers:     []v1.Container{testContainer("100m", "200m"), testContainer("50m", "300m")},
+	}}, "500m", "1", true},
+	// init container is smaller than the app containers
+	{v1.Pod{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{testContainer("120m", "150m")},
+		Containers:     []v1.Container{testContainer("100m", "200m"), testContainer("50m", "300m")},
+	}}, "150m", "500m", true},
+	// init container without a limit means the pod has no limit
+	{v1.Pod{Spec: v1.PodSpec{
+		InitContainers: []v1.Container{testContainer("120m", "")},
+		Containers:     []v1.Container{testContainer("100m", "200m")},
+	}}, "120m", "0", false},
+	// sidecars are added to the app containers and to the init containers that start after them
+	{testSidecarPod(v1.PodSpec{
+		InitContainers: []v1.Container{
+			testNamedContainer("sidecar", "100m", "100m"),
+			testNamedContainer("init", "400m", "400m"),
+		},
+		Containers: []v1.Container{testContainer("200m", "200m")},
+	}, "sidecar"), "500m", "500m", true},
+	// sidecars are added to the app containers
+	{testSidecarPod(v1.PodSpec{
+		InitContainers: []v1.Container{
+			testNamedContainer("init", "200m", "200m"),
+			testNamedContainer("sidecar", "100m", "100m"),
+		},
+		Containers: []v1.Container{testContainer("200m", "200m")},
+	}, "sidecar"), "300m", "300m", true},
+	// overhead is added to the result
+	{v1.Pod{Spec: v1.PodSpec{
+		Containers: []v1.Container{testContainer("100m", "200m")},
+		Overhead:   v1.ResourceList{v1.ResourceCPU: apires.MustParse("250m")},
+	}}, "350m", "450m", true},
+}
+
+func TestPodEffectiveResources(t *testing.T) {
+
+	for _, test := range podEffectiveResourcesTests {
+		request, limit, limited := podEffectiveResources(test.arg1, v1.ResourceCPU)
+		if request.Cmp(apires.MustParse(test.expectedRequest)) != 0 {
+			t.Errorf("Request %s not equal to expected %s", request.String(), test.expectedRequest)
+		}
+		if limit.Cmp(apires.MustParse(test.expectedLimit)) != 0 {
+			t.Errorf("Limit %s not equal to expected %s", limit.String(), test.expectedLimit)
+		}
+		if limited != test.expectedLimited {
+			t.Errorf("Limited %t not equal to expected %t", limited, test.expectedLimited)
+		}
+	}
+
+}
+
+func TestPodEffectiveResourcesExtended(t *testing.T) {
+	gpu := v1.ResourceName("nvidia.com/gpu")
+
+	// extended resources only get what was asked for so a missing limit is not unbounded
+	pod := v1.Pod{Spec: v1.PodSpec{Containers: []v1.Container{
+		{Resources: v1.ResourceRequirements{
+			Requests: v1.ResourceList{gpu: apires.MustParse("1")},
+			Limits:   v1.ResourceList{gpu: apires.MustParse("1")},
+		}},
+		testContainer("100m", ""),
+	}}}
+
+	_, limit, limited := podEffectiveResources(pod, gpu)
+	if !limited || limit.Cmp(apires.MustParse("1")) != 0 {
+		t.Errorf("Output %s %t not equal to expected 1 true", limit.String(), limited)
+	}
+
+	if _, _, limited := podEffectiveResources(pod, v1.ResourceMemory); limited {
+		t.Errorf("Output %t not equal to expected false", limited)
+	}
+
+}
+
+// ********************
+// statsProcessTableRow
+// ********************
+type statsProcessTableRowTest struct {
+	resource string
+	arg1     v1.Container
+	expected string // LIMIT column
+}
+
+var statsProcessTableRowTests = []statsProcessTableRowTest{
+	{"cpu", testContainer("100m", "200m"), "200m"},
+	{"cpu", testContainer("100m", ""), noLimitText},
+	{"memory", testContainer("100m", ""), noLimitText},
+	// extended resources only get what was asked for
+	{"nvidia.com/gpu", testContainer("100m", ""), "0"},
+}
+
+func TestStatsProcessTableRow(t *testing.T) {
+
+	for _, test := range statsProcessTableRowTests {
+		s := resource{ResourceType: test.resource}
+		row := s.statsProcessTableRow(test.arg1.Resources, v1.ResourceList{}, BuilderInformation{}, test.resource)
+		if output := row[2].text; output != test.expected {
+			t.Errorf("Output %s not equal to expected %s for %s", output, test.expected, test.resource)
+		}
+	}
+
+}
+
+// ***********
+// podQOSClass
+// ***********
+type podQOSClassTest struct {
+	arg1     []v1.Container
+	expected v1.PodQOSClass
+}
+
+var podQOSClassTests = []podQOSClassTest{
+	{[]v1.Container{testContainer("", "")}, v1.PodQOSBestEffort},
+	{[]v1.Container{testContainer("", ""), testContainer("0", "")}, v1.PodQOSBestEffort},
+	{[]v1.Container{testContainer("100m", "")}, v1.PodQOSBurstable},
+	{[]v1.Container{testContainer("100m", "200m")}, v1.PodQOSBurstable},
+	{[]v1.Container{testContainer("1", "1"), testContainer("", "")}, v1.PodQOSBurstable},
+	{[]v1.Container{testContainer("1", "1")}, v1.PodQOSGuaranteed},
+	{[]v1.Container{testContainer("", "1"), testContainer("2", "2")}, v1.PodQOSGuaranteed},
+}
+
+func TestPodQOSClass(t *testing.T) {
+
+	for _, test := range podQOSClassTests {
+		pod := v1.Pod{Spec: v1.PodSpec{Containers: test.arg1}}
+		if output := podQOSClass(pod); output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+
+}
@@ -1,7 +1,9 @@
 package plugin
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/spf13/cobra"
 	v1 "k8s.io/api/core/v1"
@@ -12,6 +14,13 @@ var securityShort = "Shows details of configured container security settings"
 
 var securityDescription = ` View SecurityContext configuration that has been applied to the containers. Shows 
 runAsUser and runAsGroup fields among others.
+
+Use --extended to also show the seccomp and AppArmor profiles, procMount, windows options, sysctls, fsGroup
+and supplemental groups. Each extended value starts with where it was set, P: for the pod security context,
+C: for the container security context and A: for a pod annotation. --extended can not be used with --selinux.
+
+Use --image-config-dir to point at a directory holding OCI image layouts or docker save output, IMAGE_USER
+then shows the USER the image runs as when runAsUser has not been set.
 `
 
 var securityExample = `  # List container security info from pods
@@ -39,7 +48,10 @@ var securityExample = `  # List container security info from pods
   %[1]s security -l app=web
 
   # List container security info from all pods where the pod label app is either web or mail
-  %[1]s security -l "app in (web,mail)"`
+  %[1]s security -l "app in (web,mail)"
+
+  # List the extended security info including seccomp and AppArmor profiles
+  %[1]s security --extended`
 
 // list details of configured liveness readiness and startup security
 func Security(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
@@ -75,6 +87,20 @@ func Security(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 		loopinfo.ShowSELinuxOptions = true
 	}
 
+	if cmd.Flag("extended").Value.String() == "true" {
+		if loopinfo.ShowSELinuxOptions {
+			return errors.New("error: --selinux and --extended can not be used together")
+		}
+		loopinfo.ShowExtended = true
+	}
+
+	if dir := cmd.Flag("image-config-dir").Value.String(); len(dir) > 0 {
+		loopinfo.ImageConfigs, err = loadImageConfigDir(dir)
+		if err != nil {
+			return err
+		}
+	}
+
 	if err := builder.Build(&loopinfo); err != nil {
 		return err
 	}
@@ -90,6 +116,8 @@ func Security(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args
 
 type security struct {
 	ShowSELinuxOptions bool
+	ShowExtended       bool
+	ImageConfigs       *imageConfigStore
 }
 
 func (s *security) Headers() []string {
@@ -108,6 +136,15 @@ func (s *security) Headers() []string {
 			"RUN_AS_NON_ROOT",
 			"RUN_AS_USER",
 			"RUN_AS_GROUP",
+			"SECCOMP",
+			"APPARMOR",
+			"PROC_MOUNT",
+			"WINDOWS_OPTIONS",
+			"SYSCTLS",
+			"FS_GROUP",
+			"FS_GROUP_CHANGE_POLICY",
+			"SUPPLEMENTAL_GROUPS",
+			"IMAGE_USER",
 		}
 	}
 }
@@ -121,18 +158,25 @@ func (s *security) BuildEphemeralContainerStatus(container v1.ContainerStatus, i
 }
 
 func (s *security) HideColumns(info BuilderInformation) []int {
-	return []int{}
-}
-
-func (s *security) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	var rowOut []Cell
+	var hideColumns []int
 
 	if s.ShowSELinuxOptions {
-		rowOut = make([]Cell, 4)
-	} else {
-		rowOut = make([]Cell, 6)
+		return hideColumns
+	}
+
+	if !s.ShowExtended {
+		hideColumns = append(hideColumns, 6, 7, 8, 9, 10, 11, 12, 13)
+	}
+
+	if s.ImageConfigs == nil {
+		hideColumns = append(hideColumns, 14)
 	}
-	return rowOut, nil
+
+	return hideColumns
+}
+
+func (s *security) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	return make([]Cell, len(s.Headers())), nil
 }
 
 func (s *security) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
@@ -140,7 +184,7 @@ func (s *security) BuildContainerSpec(container v1.Container, info BuilderInform
 	if s.ShowSELinuxOptions {
 		out[0] = s.seLinuxBuildRow(info, container.SecurityContext, info.Data.pod.Spec.SecurityContext)
 	} else {
-		out[0] = s.securityBuildRow(info, container.SecurityContext, info.Data.pod.Spec.SecurityContext)
+		out[0] = s.securityBuildRow(info, container.Image, container.SecurityContext, info.Data.pod.Spec.SecurityContext)
 	}
 	return out, nil
 }
@@ -150,12 +194,12 @@ func (s *security) BuildEphemeralContainerSpec(container v1.EphemeralContainer,
 	if s.ShowSELinuxOptions {
 		out[0] = s.seLinuxBuildRow(info, container.SecurityContext, info.Data.pod.Spec.SecurityContext)
 	} else {
-		out[0] = s.securityBuildRow(info, container.SecurityContext, info.Data.pod.Spec.SecurityContext)
+		out[0] = s.securityBuildRow(info, container.Image, container.SecurityContext, info.Data.pod.Spec.SecurityContext)
 	}
 	return out, nil
 }
 
-func (s *security) securityBuildRow(info BuilderInformation, csc *v1.SecurityContext, psc *v1.PodSecurityContext) []Cell {
+func (s *security) securityBuildRow(info BuilderInformation, imageName string, csc *v1.SecurityContext, psc *v1.PodSecurityContext) []Cell {
 	var cellList []Cell
 	ape := Cell{}
 	p := Cell{}
@@ -200,7 +244,7 @@ func (s *security) securityBuildRow(info BuilderInformation, csc *v1.SecurityCon
 		}
 
 		if csc.RunAsGroup != nil {
-			rag = NewCellInt(fmt.Sprintf("%d", *psc.RunAsGroup), *csc.RunAsGroup)
+			rag = NewCellInt(fmt.Sprintf("%d", *csc.RunAsGroup), *csc.RunAsGroup)
 		}
 	}
 
@@ -216,11 +260,123 @@ func (s *security) securityBuildRow(info BuilderInformation, csc *v1.SecurityCon
 		rau,
 		rag,
 	)
+	cellList = append(cellList, s.extendedBuildCells(info, csc, psc)...)
+
+	// the image USER is only used when runAsUser hasnt been set
+	imageUser := Cell{}
+	if config, ok := s.ImageConfigs.Get(imageName); ok && len(rau.text) == 0 {
+		imageUser = NewCellText(imageUserName(config))
+	}
+	cellList = append(cellList, imageUser)
 
 	return cellList
 
 }
 
+// extendedBuildCells returns the extended security columns, each value is prefixed with P: C: or A: to
+//
+//	show if it came from the pod, the container or an annotation
+func (s *security) extendedBuildCells(info BuilderInformation, csc *v1.SecurityContext, psc *v1.PodSecurityContext) []Cell {
+	seccomp := Cell{}
+	apparmor := Cell{}
+	procMount := Cell{}
+	windows := Cell{}
+	sysctls := Cell{}
+	fsGroup := Cell{}
+	fsGroupPolicy := Cell{}
+	groups := Cell{}
+
+	annotations := info.Data.pod.Annotations
+
+	// seccomp in order of precedence, container field, container annotation, pod field then pod annotation
+	if csc != nil && csc.SeccompProfile != nil {
+		seccomp = NewCellText("C:" + seccompProfileString(csc.SeccompProfile))
+	} else if value, ok := annotations[v1.SeccompContainerAnnotationKeyPrefix+info.Name]; ok {
+		seccomp = NewCellText("A:" + value)
+	} else if psc != nil && psc.SeccompProfile != nil {
+		seccomp = NewCellText("P:" + seccompProfileString(psc.SeccompProfile))
+	} else if value, ok := annotations[v1.SeccompPodAnnotationKey]; ok {
+		seccomp = NewCellText("A:" + value)
+	}
+
+	// the AppArmor field is not part of this api version so only the annotation can be checked
+	if value, ok := annotations[v1.AppArmorBetaContainerAnnotationKeyPrefix+info.Name]; ok {
+		apparmor = NewCellText("A:" + value)
+	}
+
+	if csc != nil && csc.ProcMount != nil {
+		procMount = NewCellText("C:" + string(*csc.ProcMount))
+	}
+
+	if csc != nil && csc.WindowsOptions != nil {
+		windows = NewCellText("C:" + windowsOptionsString(csc.WindowsOptions))
+	} else if psc != nil && psc.WindowsOptions != nil {
+		windows = NewCellText("P:" + windowsOptionsString(psc.WindowsOptions))
+	}
+
+	if psc != nil {
+		if len(psc.Sysctls) > 0 {
+			list := []string{}
+			for _, sysctl := range psc.Sysctls {
+				list = append(list, sysctl.Name+"="+sysctl.Value)
+			}
+			sysctls = NewCellText("P:" + strings.Join(list, ","))
+		}
+
+		if psc.FSGroup != nil {
+			fsGroup = NewCellText(fmt.Sprintf("P:%d", *psc.FSGroup))
+		}
+
+		if psc.FSGroupChangePolicy != nil {
+			fsGroupPolicy = NewCellText("P:" + string(*psc.FSGroupChangePolicy))
+		}
+
+		if len(psc.SupplementalGroups) > 0 {
+			list := []string{}
+			for _, group := range psc.SupplementalGroups {
+				list = append(list, fmt.Sprintf("%d", group))
+			}
+			groups = NewCellText("P:" + strings.Join(list, ","))
+		}
+	}
+
+	return []Cell{
+		seccomp,
+		apparmor,
+		procMount,
+		windows,
+		sysctls,
+		fsGroup,
+		fsGroupPolicy,
+		groups,
+	}
+}
+
+// seccompProfileString returns the profile type along with the localhost path when set
+func seccompProfileString(profile *v1.SeccompProfile) string {
+	if profile.LocalhostProfile != nil {
+		return string(profile.Type) + "/" + *profile.LocalhostProfile
+	}
+	return string(profile.Type)
+}
+
+// windowsOptionsString returns the set windows options as a comma seperated list
+func windowsOptionsString(options *v1.WindowsSecurityContextOptions) string {
+	list := []string{}
+
+	if options.GMSACredentialSpecName != nil {
+		list = append(list, "gmsa="+*options.GMSACredentialSpecName)
+	}
+	if options.RunAsUserName != nil {
+		list = append(list, "runAsUserName="+*options.RunAsUserName)
+	}
+	if options.HostProcess != nil {
+		list = append(list, fmt.Sprintf("hostProcess=%t", *options.HostProcess))
+	}
+
+	return strings.Join(list, ",")
+}
+
 func (s *security) seLinuxBuildRow(info BuilderInformation, csc *v1.SecurityContext, psc *v1.PodSecurityContext) []Cell {
 	var cellList []Cell
 	seLevel := Cell{}
@@ -251,7 +407,7 @@ func (s *security) seLinuxBuildRow(info BuilderInformation, csc *v1.SecurityCont
 
 	if csc != nil {
 		if csc.SELinuxOptions != nil {
-			cselinux := psc.SELinuxOptions
+			cselinux := csc.SELinuxOptions
 			if len(cselinux.Level) > 0 {
 				seLevel = NewCellText(cselinux.Level)
 			}
@@ -20,7 +20,14 @@ are shown slong with current ready and running state. Pods and containers can al
 by name. If no name is specified the container state of all pods in the current namespace is
 shown.
 
-The T column in the table output denotes S for Standard and I for init containers`
+The T column in the table output denotes S for Standard and I for init containers
+
+The MEANING column decodes the exit code and signal of terminated containers, for example 137 is shown as
+OOMKilled when the reason is OOMKilled otherwise as SIGKILL. Using --previous explains why the container
+last restarted. Extra exit codes can be added, or the built in ones replaced, using exitCodes in the config
+file $HOME/.config/kubectl-ice/config.yaml:
+  exitCodes:
+    3: "unable to read the application config"`
 
 var statusExample = `  # List individual container status from pods
   %[1]s status
@@ -34,6 +41,9 @@ var statusExample = `  # List individual container status from pods
   # List previous container status from a single pod
   %[1]s status -p my-pod-4jh36
 
+  # List why containers last restarted, only showing containers that were killed
+  %[1]s status -p -m "EXIT-CODE>128"
+
   # List status of all containers named web-container searching all 
   # pods in the current namespace
   %[1]s status -c web-container
@@ -74,6 +84,10 @@ func Status(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args [
 	connect.Flags = commonFlagList
 
 	loopinfo := status{}
+	loopinfo.ExitCodes, err = loadExitCodeMeanings()
+	if err != nil {
+		return err
+	}
 	builder.Connection = &connect
 	builder.SetFlagsFrom(commonFlagList)
 
@@ -123,6 +137,7 @@ type status struct {
 	ShowPrevious bool
 	ShowDetails  bool
 	ShowID       bool // container id
+	ExitCodes    map[int32]string
 
 	pNotReady     bool // Ready - we use the inverted term so the code makes more sense
 	pStopped      bool // Started - we use the inverted term so the code makes more sense
@@ -144,6 +159,7 @@ func (s *status) Headers() []string {
 		"TIMESTAMP",
 		"AGE",
 		"MESSAGE",
+		"MEANING",
 	}
 }
 
@@ -155,7 +171,7 @@ func (s *status) BuildEphemeralContainerSpec(container v1.EphemeralContainer, in
 }
 
 func (s *status) HideColumns(info BuilderInformation) []int {
-	// "READY","STARTED","RESTARTS","STATE","REASON","EXIT-CODE","SIGNAL","ID","TIMESTAMP","AGE","MESSAGE",
+	// "READY","STARTED","RESTARTS","STATE","REASON","EXIT-CODE","SIGNAL","ID","TIMESTAMP","AGE","MESSAGE","MEANING",
 	var hideColumns []int
 
 	if s.ShowDetails {
@@ -186,7 +202,7 @@ func (s *status) HideColumns(info BuilderInformation) []int {
 }
 
 func (s *status) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
-	rowOut := make([]Cell, 11)
+	rowOut := make([]Cell, 12)
 
 	// rowOut[0] // ready
 	// rowOut[1] // started
@@ -199,6 +215,7 @@ func (s *status) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, er
 	// rowOut[8] // timestamp
 	// rowOut[9] // age
 	// rowOut[10] // message
+	// rowOut[11] // meaning
 
 	rowOut[0].text = "true"
 	rowOut[1].text = "true"
@@ -242,6 +259,7 @@ func (s *status) BuildContainerStatus(container v1.ContainerStatus, info Builder
 	var exitCode string
 	var signal string
 	var message string
+	var meaning string
 	var startedAt string
 	var startTime time.Time
 	var skipAgeCalculation bool
@@ -279,6 +297,7 @@ func (s *status) BuildContainerStatus(container v1.ContainerStatus, info Builder
 		startedAt = state.Terminated.StartedAt.Format(timestampFormat)
 		reason = state.Terminated.Reason
 		message = state.Terminated.Message
+		meaning = exitCodeMeaning(s.ExitCodes, state.Terminated.ExitCode, state.Terminated.Signal, reason)
 	}
 
 	if state.Running != nil {
@@ -317,7 +336,7 @@ func (s *status) BuildContainerStatus(container v1.ContainerStatus, info Builder
 
 	// container.ContainerID
 
-	// READY STARTED RESTARTS STATE REASON EXIT-CODE SIGNAL TIMESTAMP AGE MESSAGE
+	// READY STARTED RESTARTS STATE REASON EXIT-CODE SIGNAL ID TIMESTAMP AGE MESSAGE MEANING
 	cellList = append(cellList,
 		NewCellText(ready),
 		NewCellText(started),
@@ -330,6 +349,7 @@ func (s *status) BuildContainerStatus(container v1.ContainerStatus, info Builder
 		NewCellText(startedAt),
 		NewCellText(age),
 		NewCellText(message),
+		NewCellText(meaning),
 	)
 
 	log.Debug("len(cellList) =", len(cellList))
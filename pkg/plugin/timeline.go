@@ -0,0 +1,365 @@
+package plugin
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/cli-runtime/pkg/genericclioptions"
+)
+
+// width of the gantt bar drawn in the TIMELINE column
+const timelineWidth = 40
+
+var timelineShort = "Show how long each step of starting a pod took"
+
+var timelineDescription = ` Prints a timeline of each pod starting up, from when it was created to when it became Ready. The
+steps are listed in order, when the pod was scheduled, each image pull, the start and finish of each init
+container, the start of each container and the time taken for the pod to become Ready.
+
+START is the time since the pod was created that the step began, DURATION is how long the step took and the
+TIMELINE column draws each step as a bar so the slow steps stand out. Containers that are still waiting to
+start are listed without a START. TOTAL is the time the pod took to become Ready, this is the same on every
+row of the pod so --sort TOTAL can be used to find slow starting pods. Only the last time the pod became Ready
+is kept so a pod that has lost and regained readiness, for example after a container restart, shows the time
+to its latest Ready rather than its first.
+
+Image pulls come from the pods events so they are only shown when connected to a live cluster and only for
+images that were not already present on the node.`
+
+var timelineExample = `  # Show the startup timeline of pods in the current namespace
+  %[1]s timeline
+
+  # Show the startup timeline of a single pod
+  %[1]s timeline my-pod-4jh36
+
+  # Find the slowest starting pods of a deployment, slowest first
+  %[1]s timeline -l app=web --sort '!TOTAL'
+
+  # Only show the init containers
+  %[1]s timeline -m "STEP=init"`
+
+func Timeline(cmd *cobra.Command, kubeFlags *genericclioptions.ConfigFlags, args []string) error {
+
+	log := logger{location: "Timeline"}
+	log.Debug("Start")
+
+	loopinfo := timeline{}
+	builder := RowBuilder{}
+	builder.LoopPod = true
+	builder.PodName = args
+
+	connect := Connector{}
+	if err := connect.LoadConfig(kubeFlags); err != nil {
+		return err
+	}
+
+	commonFlagList, err := processCommonFlags(cmd)
+	if err != nil {
+		return err
+	}
+	connect.Flags = commonFlagList
+	builder.Connection = &connect
+	builder.SetFlagsFrom(commonFlagList)
+
+	stdinChanged, err := builder.HasStdinChanged()
+	if err != nil {
+		return err
+	}
+	loopinfo.Live = len(commonFlagList.inputFilename) == 0 && !stdinChanged
+	loopinfo.Connection = &connect
+
+	table := Table{}
+	builder.Table = &table
+	builder.ShowTreeView = commonFlagList.showTreeView
+
+	if err := builder.Build(&loopinfo); err != nil {
+		return err
+	}
+
+	if err := table.SortByNames(commonFlagList.sortList...); err != nil {
+		return err
+	}
+
+	outputTableAs(table, commonFlagList.outputAs)
+	return nil
+
+}
+
+// timelineStep is a single step of a pod starting, end is zero for steps that havent finished
+type timelineStep struct {
+	step  string
+	name  string
+	start time.Time
+	end   time.Time
+}
+
+type timeline struct {
+	Connection *Connector
+	Live       bool // image pulls are only read from the events of a live cluster
+}
+
+func (s *timeline) Headers() []string {
+	return []string{
+		"STEP", "CONTAINER-NAME", "START", "DURATION", "TOTAL", "TIMELINE",
+	}
+}
+
+func (s *timeline) BuildContainerStatus(container v1.ContainerStatus, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *timeline) BuildContainerSpec(container v1.Container, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *timeline) BuildEphemeralContainerSpec(container v1.EphemeralContainer, info BuilderInformation) ([][]Cell, error) {
+	return [][]Cell{}, nil
+}
+
+func (s *timeline) HideColumns(info BuilderInformation) []int {
+	return []int{}
+}
+
+func (s *timeline) BuildBranch(info BuilderInformation, rows [][]Cell) ([]Cell, error) {
+	rowOut := make([]Cell, 6)
+
+	// "STEP", "CONTAINER-NAME", "START", "DURATION", "TOTAL", "TIMELINE"
+	if info.TypeName == TypeNamePod {
+		rowOut[0] = NewCellText("startup")
+		rowOut[4] = s.totalCell(info.Data.pod)
+		return rowOut, nil
+	}
+
+	// owners show their slowest pod
+	for _, r := range rows {
+		if r[4].typ == 1 && r[4].number >= rowOut[4].number {
+			rowOut[4] = r[4]
+		}
+	}
+	rowOut[0] = NewCellText("startup")
+
+	return rowOut, nil
+}
+
+func (s *timeline) BuildPod(pod v1.Pod, info BuilderInformation) ([][]Cell, error) {
+	out := [][]Cell{}
+
+	steps, err := s.podSteps(pod)
+	if err != nil {
+		return out, err
+	}
+
+	created := pod.CreationTimestamp.Time
+
+	// the bars are scaled so the latest step reaches the end of the column
+	span := time.Duration(0)
+	for _, step := range steps {
+		for _, t := range []time.Time{step.start, step.end} {
+			if !t.IsZero() && t.Sub(created) > span {
+				span = t.Sub(created)
+			}
+		}
+	}
+
+	total := s.totalCell(pod)
+	for _, step := range steps {
+		if step.start.IsZero() {
+			// the container is still waiting to start
+			out = append(out, []Cell{
+				NewCellText(step.step),
+				NewCellText(step.name),
+				Cell{},
+				Cell{},
+				total,
+				Cell{},
+			})
+			continue
+		}
+
+		start := step.start.Sub(created)
+		duration := Cell{}
+		if !step.end.IsZero() {
+			d := step.end.Sub(step.start)
+			duration = NewCellInt(timelineDuration(d), int64(d.Seconds()))
+		}
+
+		out = append(out, []Cell{
+			NewCellText(step.step),
+			NewCellText(step.name),
+			NewCellInt("+"+timelineDuration(start), int64(start.Seconds())),
+			duration,
+			total,
+			NewCellText(timelineBar(step, created, span)),
+		})
+	}
+
+	return out, nil
+}
+
+// podSteps lists the steps taken to start the pod in the order they happen, containers that havent started
+//
+//	are included with a zero start time
+func (s *timeline) podSteps(pod v1.Pod) ([]timelineStep, error) {
+	var steps []timelineStep
+
+	created := pod.CreationTimestamp.Time
+
+	var events []v1.Event
+	if s.Live {
+		var err error
+		events, err = s.Connection.GetPodEvents(pod)
+		if err != nil {
+			return steps, err
+		}
+	}
+
+	if scheduled, ok := podConditionTime(pod, v1.PodScheduled); ok {
+		steps = append(steps, timelineStep{step: "scheduled", start: created, end: scheduled})
+	}
+
+	for _, container := range pod.Spec.InitContainers {
+		if pull, ok := imagePullStep(events, container.Name); ok {
+			steps = append(steps, pull)
+		}
+
+		step := timelineStep{step: "init", name: container.Name}
+		if status := containerStatusByName(pod, container.Name); status != nil {
+			switch {
+			case status.State.Terminated != nil:
+				step.start = status.State.Terminated.StartedAt.Time
+				step.end = status.State.Terminated.FinishedAt.Time
+			case status.State.Running != nil:
+				step.start = status.State.Running.StartedAt.Time
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	containersReady, _ := podConditionTime(pod, v1.ContainersReady)
+	for _, container := range pod.Spec.Containers {
+		if pull, ok := imagePullStep(events, container.Name); ok {
+			steps = append(steps, pull)
+		}
+
+		// containers are ready once they have started and passed their readiness probe
+		step := timelineStep{step: "container", name: container.Name}
+		if status := containerStatusByName(pod, container.Name); status != nil {
+			switch {
+			case status.State.Running != nil:
+				step.start = status.State.Running.StartedAt.Time
+			case status.State.Terminated != nil:
+				step.start = status.State.Terminated.StartedAt.Time
+			}
+			if status.Ready && containersReady.After(step.start) {
+				step.end = containersReady
+			}
+		}
+		steps = append(steps, step)
+	}
+
+	if ready, ok := podConditionTime(pod, v1.PodReady); ok {
+		steps = append(steps, timelineStep{step: "ready", start: created, end: ready})
+	}
+
+	return steps, nil
+}
+
+// totalCell returns the time the pod took to become Ready, an empty cell is returned for pods that are not Ready.
+//
+//	Only the latest Ready transition is recorded by kubernetes so a pod that has flapped shows its latest Ready
+func (s *timeline) totalCell(pod v1.Pod) Cell {
+	ready, ok := podConditionTime(pod, v1.PodReady)
+	if !ok {
+		return Cell{}
+	}
+
+	total := ready.Sub(pod.CreationTimestamp.Time)
+	return NewCellInt(timelineDuration(total), int64(total.Seconds()))
+}
+
+// podConditionTime returns when the condition last changed, ok is false when the condition isnt True
+func podConditionTime(pod v1.Pod, conditionType v1.PodConditionType) (time.Time, bool) {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == conditionType && condition.Status == v1.ConditionTrue {
+			return condition.LastTransitionTime.Time, !condition.LastTransitionTime.IsZero()
+		}
+	}
+	return time.Time{}, false
+}
+
+// imagePullStep uses the Pulling and Pulled events of a container to work out how long its image took to pull,
+//
+//	ok is false when the image was already on the node
+func imagePullStep(events []v1.Event, containerName string) (timelineStep, bool) {
+	step := timelineStep{step: "pull", name: containerName}
+
+	for _, event := range events {
+		if eventContainerName(event) != containerName {
+			continue
+		}
+		switch event.Reason {
+		case "Pulling":
+			if seen := eventLastSeen(event); step.start.IsZero() || seen.After(step.start) {
+				step.start = seen
+			}
+		case "Pulled":
+			if strings.Contains(event.Message, "already present") {
+				continue
+			}
+			if seen := eventLastSeen(event); seen.After(step.end) {
+				step.end = seen
+			}
+		}
+	}
+
+	if step.start.IsZero() {
+		return step, false
+	}
+	if step.end.Before(step.start) {
+		// the image is still being pulled
+		step.end = time.Time{}
+	}
+
+	return step, true
+}
+
+// timelineDuration shows a duration rounded to the nearest second
+func timelineDuration(d time.Duration) string {
+	if d < 0 {
+		d = 0
+	}
+	return d.Round(time.Second).String()
+}
+
+// timelineBar draws the step as a bar scaled to span, steps that havent finished are drawn as a single >
+func timelineBar(step timelineStep, created time.Time, span time.Duration) string {
+	if span <= 0 {
+		return "#"
+	}
+
+	scale := func(t time.Time) int {
+		pos := int(float64(t.Sub(created)) / float64(span) * timelineWidth)
+		if pos < 0 {
+			pos = 0
+		}
+		if pos > timelineWidth {
+			pos = timelineWidth
+		}
+		return pos
+	}
+
+	start := scale(step.start)
+	if step.end.IsZero() {
+		return fmt.Sprintf("%s>", strings.Repeat(".", start))
+	}
+
+	length := scale(step.end) - start
+	if length < 1 {
+		length = 1
+	}
+
+	return strings.Repeat(".", start) + strings.Repeat("#", length)
+}
@@ -0,0 +1,191 @@
+package plugin
+
+import (
+	"testing"
+	"time"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var timelineCreated = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+// timelineAt returns the time the number of seconds after the test pod was created
+func timelineAt(seconds int) time.Time {
+	return timelineCreated.Add(time.Duration(seconds) * time.Second)
+}
+
+// ***********
+// timelineBar
+// ***********
+type timelineBarTest struct {
+	step     timelineStep
+	span     time.Duration
+	expected string
+}
+
+var timelineBarTests = []timelineBarTest{
+	{timelineStep{start: timelineAt(0), end: timelineAt(40)}, 40 * time.Second, "########################################"},
+	{timelineStep{start: timelineAt(10), end: timelineAt(20)}, 40 * time.Second, "..........##########"},
+	// very short steps are always drawn
+	{timelineStep{start: timelineAt(20), end: timelineAt(20)}, 40 * time.Second, "....................#"},
+	// steps that havent finished
+	{timelineStep{start: timelineAt(30)}, 40 * time.Second, "..............................>"},
+	// steps outside the span are kept inside the column
+	{timelineStep{start: timelineAt(-5), end: timelineAt(80)}, 40 * time.Second, "########################################"},
+	{timelineStep{start: timelineAt(0), end: timelineAt(0)}, 0, "#"},
+}
+
+func TestTimelineBar(t *testing.T) {
+
+	for _, test := range timelineBarTests {
+		if output := timelineBar(test.step, timelineCreated, test.span); output != test.expected {
+			t.Errorf("Output %q not equal to expected %q", output, test.expected)
+		}
+	}
+
+}
+
+// *************
+// imagePullStep
+// *************
+func timelineTestEvent(container string, reason string, message string, seconds int) v1.Event {
+	return v1.Event{
+		InvolvedObject: v1.ObjectReference{FieldPath: "spec.containers{" + container + "}"},
+		Reason:         reason,
+		Message:        message,
+		LastTimestamp:  metav1.NewTime(timelineAt(seconds)),
+	}
+}
+
+type imagePullStepTest struct {
+	container     string
+	expectedOk    bool
+	expectedStart time.Time
+	expectedEnd   time.Time
+}
+
+var imagePullStepTests = []imagePullStepTest{
+	{"web", true, timelineAt(5), timelineAt(25)},
+	// the image was already on the node
+	{"cached", false, time.Time{}, time.Time{}},
+	// still pulling
+	{"slow", true, timelineAt(8), time.Time{}},
+	// the newest Pulling event is used after a failed pull
+	{"retry", true, timelineAt(30), timelineAt(35)},
+	{"missing", false, time.Time{}, time.Time{}},
+}
+
+var imagePullStepEvents = []v1.Event{
+	timelineTestEvent("web", "Pulling", `Pulling image "nginx:1.21"`, 5),
+	timelineTestEvent("web", "Pulled", `Successfully pulled image "nginx:1.21" in 20s`, 25),
+	timelineTestEvent("web", "Started", "Started container web", 26),
+	timelineTestEvent("cached", "Pulled", `Container image "redis:7" already present on machine`, 5),
+	timelineTestEvent("slow", "Pulling", `Pulling image "big:1.0"`, 8),
+	timelineTestEvent("retry", "Pulling", `Pulling image "app:1.0"`, 5),
+	timelineTestEvent("retry", "Failed", "Failed to pull image", 10),
+	timelineTestEvent("retry", "Pulling", `Pulling image "app:1.0"`, 30),
+	timelineTestEvent("retry", "Pulled", `Successfully pulled image "app:1.0" in 5s`, 35),
+}
+
+func TestImagePullStep(t *testing.T) {
+
+	for _, test := range imagePullStepTests {
+		step, ok := imagePullStep(imagePullStepEvents, test.container)
+		if ok != test.expectedOk {
+			t.Errorf("Output %t not equal to expected %t for %s", ok, test.expectedOk, test.container)
+			continue
+		}
+		if !ok {
+			continue
+		}
+		if !step.start.Equal(test.expectedStart) || !step.end.Equal(test.expectedEnd) {
+			t.Errorf("Output %s - %s not equal to expected %s - %s for %s", step.start, step.end, test.expectedStart, test.expectedEnd, test.container)
+		}
+	}
+
+}
+
+// ********
+// podSteps
+// ********
+func timelineTestPod() v1.Pod {
+	return v1.Pod{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(timelineCreated)},
+		Spec: v1.PodSpec{
+			InitContainers: []v1.Container{{Name: "migrate"}},
+			Containers:     []v1.Container{{Name: "web"}, {Name: "sidecar"}},
+		},
+		Status: v1.PodStatus{
+			Conditions: []v1.PodCondition{
+				{Type: v1.PodScheduled, Status: v1.ConditionTrue, LastTransitionTime: metav1.NewTime(timelineAt(2))},
+				{Type: v1.ContainersReady, Status: v1.ConditionFalse, LastTransitionTime: metav1.NewTime(timelineAt(45))},
+				{Type: v1.PodReady, Status: v1.ConditionFalse, LastTransitionTime: metav1.NewTime(timelineAt(45))},
+			},
+			InitContainerStatuses: []v1.ContainerStatus{{
+				Name: "migrate",
+				State: v1.ContainerState{Terminated: &v1.ContainerStateTerminated{
+					StartedAt:  metav1.NewTime(timelineAt(10)),
+					FinishedAt: metav1.NewTime(timelineAt(40)),
+				}},
+			}},
+			ContainerStatuses: []v1.ContainerStatus{
+				{Name: "web", State: v1.ContainerState{Running: &v1.ContainerStateRunning{StartedAt: metav1.NewTime(timelineAt(45))}}},
+				{Name: "sidecar", State: v1.ContainerState{Waiting: &v1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}},
+			},
+		},
+	}
+}
+
+func TestPodSteps(t *testing.T) {
+	s := timeline{}
+
+	steps, err := s.podSteps(timelineTestPod())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []timelineStep{
+		{step: "scheduled", start: timelineCreated, end: timelineAt(2)},
+		{step: "init", name: "migrate", start: timelineAt(10), end: timelineAt(40)},
+		{step: "container", name: "web", start: timelineAt(45)},
+		// waiting containers are listed without a start
+		{step: "container", name: "sidecar"},
+	}
+
+	if len(steps) != len(expected) {
+		t.Fatalf("Output %d steps not equal to expected %d", len(steps), len(expected))
+	}
+	for i, step := range expected {
+		output := steps[i]
+		if output.step != step.step || output.name != step.name || !output.start.Equal(step.start) || !output.end.Equal(step.end) {
+			t.Errorf("Output %+v not equal to expected %+v", output, step)
+		}
+	}
+
+	// once ready the containers finish at ContainersReady and the pod gets a ready step
+	pod := timelineTestPod()
+	pod.Status.Conditions[1].Status = v1.ConditionTrue
+	pod.Status.Conditions[2].Status = v1.ConditionTrue
+	pod.Status.Conditions[1].LastTransitionTime = metav1.NewTime(timelineAt(60))
+	pod.Status.Conditions[2].LastTransitionTime = metav1.NewTime(timelineAt(60))
+	pod.Status.ContainerStatuses[0].Ready = true
+
+	steps, err = s.podSteps(pod)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !steps[2].end.Equal(timelineAt(60)) {
+		t.Errorf("Output %s not equal to expected %s", steps[2].end, timelineAt(60))
+	}
+	last := steps[len(steps)-1]
+	if last.step != "ready" || !last.end.Equal(timelineAt(60)) {
+		t.Errorf("Output %+v not equal to expected a ready step ending at %s", last, timelineAt(60))
+	}
+
+	if total := s.totalCell(pod); total.number != 60 {
+		t.Errorf("Output %d not equal to expected 60", total.number)
+	}
+
+}
@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"math"
 	"strings"
+	"time"
 
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -127,3 +129,72 @@ func portAsString(port intstr.IntOrString) string {
 
 	return ""
 }
+
+// containerStatusByName returns the status of the named init, standard or ephemeral container, container names
+//
+//	are unique within a pod so the first match is always the right one, nil is returned when there is no status
+func containerStatusByName(pod v1.Pod, containerName string) *v1.ContainerStatus {
+	for _, list := range [][]v1.ContainerStatus{
+		pod.Status.InitContainerStatuses,
+		pod.Status.ContainerStatuses,
+		pod.Status.EphemeralContainerStatuses,
+	} {
+		for i := range list {
+			if list[i].Name == containerName {
+				return &list[i]
+			}
+		}
+	}
+	return nil
+}
+
+// eventContainerName returns the container an event belongs to using its fieldPath, for example
+//
+//	spec.containers{web}, an empty string is returned for pod level events
+func eventContainerName(event v1.Event) string {
+	fieldPath := event.InvolvedObject.FieldPath
+
+	start := strings.Index(fieldPath, "{")
+	end := strings.LastIndex(fieldPath, "}")
+	if start < 0 || end < start {
+		return ""
+	}
+
+	return fieldPath[start+1 : end]
+}
+
+// eventCount returns the number of times the event has happened
+func eventCount(event v1.Event) int64 {
+	if event.Series != nil && event.Series.Count > 0 {
+		return int64(event.Series.Count)
+	}
+	if event.Count > 0 {
+		return int64(event.Count)
+	}
+	return 1
+}
+
+// eventFirstSeen returns when the event first happened
+func eventFirstSeen(event v1.Event) time.Time {
+	if !event.FirstTimestamp.IsZero() {
+		return event.FirstTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
+
+// eventLastSeen returns when the event last happened
+func eventLastSeen(event v1.Event) time.Time {
+	if event.Series != nil && !event.Series.LastObservedTime.IsZero() {
+		return event.Series.LastObservedTime.Time
+	}
+	if !event.LastTimestamp.IsZero() {
+		return event.LastTimestamp.Time
+	}
+	if !event.EventTime.IsZero() {
+		return event.EventTime.Time
+	}
+	return event.CreationTimestamp.Time
+}
@@ -3,7 +3,10 @@ package plugin
 import (
 	"math"
 	"testing"
+	"time"
 
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -170,3 +173,92 @@ func TestDemo(t *testing.T) {
 		}
 	}
 }
+
+// ******************
+// eventContainerName
+// ******************
+type eventContainerNameTest struct {
+	arg1     string
+	expected string
+}
+
+var eventContainerNameTests = []eventContainerNameTest{
+	{"spec.containers{web}", "web"},
+	{"spec.initContainers{setup}", "setup"},
+	{"spec.ephemeralContainers{debugger-x7k2p}", "debugger-x7k2p"},
+	{"", ""},
+	{"spec", ""},
+	{"spec.containers}web{", ""},
+}
+
+func TestEventContainerName(t *testing.T) {
+
+	for _, test := range eventContainerNameTests {
+		event := v1.Event{InvolvedObject: v1.ObjectReference{FieldPath: test.arg1}}
+		if output := eventContainerName(event); output != test.expected {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+
+}
+
+// **********
+// eventCount
+// **********
+type eventCountTest struct {
+	arg1     v1.Event
+	expected int64
+}
+
+var eventCountTests = []eventCountTest{
+	{v1.Event{}, 1},
+	{v1.Event{Count: 5}, 5},
+	{v1.Event{Count: 5, Series: &v1.EventSeries{Count: 12}}, 12},
+	{v1.Event{Count: 5, Series: &v1.EventSeries{}}, 5},
+}
+
+func TestEventCount(t *testing.T) {
+
+	for _, test := range eventCountTests {
+		if output := eventCount(test.arg1); output != test.expected {
+			t.Errorf("Output %d not equal to expected %d", output, test.expected)
+		}
+	}
+
+}
+
+// *************
+// eventLastSeen
+// *************
+var eventTestTime = time.Date(2022, 6, 1, 12, 0, 0, 0, time.UTC)
+
+type eventLastSeenTest struct {
+	arg1     v1.Event
+	expected time.Time
+}
+
+var eventLastSeenTests = []eventLastSeenTest{
+	{v1.Event{ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(eventTestTime)}}, eventTestTime},
+	{v1.Event{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(eventTestTime)},
+		EventTime:  metav1.NewMicroTime(eventTestTime.Add(time.Minute)),
+	}, eventTestTime.Add(time.Minute)},
+	{v1.Event{
+		EventTime:     metav1.NewMicroTime(eventTestTime.Add(time.Minute)),
+		LastTimestamp: metav1.NewTime(eventTestTime.Add(2 * time.Minute)),
+	}, eventTestTime.Add(2 * time.Minute)},
+	{v1.Event{
+		LastTimestamp: metav1.NewTime(eventTestTime.Add(2 * time.Minute)),
+		Series:        &v1.EventSeries{LastObservedTime: metav1.NewMicroTime(eventTestTime.Add(3 * time.Minute))},
+	}, eventTestTime.Add(3 * time.Minute)},
+}
+
+func TestEventLastSeen(t *testing.T) {
+
+	for _, test := range eventLastSeenTests {
+		if output := eventLastSeen(test.arg1); !output.Equal(test.expected) {
+			t.Errorf("Output %s not equal to expected %s", output, test.expected)
+		}
+	}
+
+}